@@ -0,0 +1,296 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"calendar_utility_node_for_timesheets/db"
+	"calendar_utility_node_for_timesheets/models"
+	"calendar_utility_node_for_timesheets/pdfgen"
+	"calendar_utility_node_for_timesheets/rules"
+)
+
+// Usage text printed for help and unknown commands
+const Usage = `Usage: timesheets <command> [flags]
+
+Commands:
+  profiles                          List saved profiles (* marks the active one)
+  list    [--profile ID]            List saved timesheets
+  show    [--profile ID] YYYY-MM[-DD]
+                                    Print the daily entries and totals of the
+                                    timesheet holding that month's 1st or that day
+  export  [--profile ID] --month YYYY-MM[-DD] [--out FILE]
+                                    Generate that timesheet's PDF
+`
+
+// Run executes a headless command against the repository and writes results to out
+func Run(repo *db.Repository, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		fmt.Fprint(out, Usage)
+		return nil
+	}
+
+	cmd, rest := args[0], args[1:]
+	switch cmd {
+	case "profiles":
+		return listProfiles(repo, out)
+	case "list":
+		return listTimesheets(repo, rest, out)
+	case "show":
+		return showTimesheet(repo, rest, out)
+	case "export":
+		return exportTimesheet(repo, rest, out)
+	case "help", "-h", "--help":
+		fmt.Fprint(out, Usage)
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q\n\n%s", cmd, Usage)
+	}
+}
+
+func listProfiles(repo *db.Repository, out io.Writer) error {
+	profiles, err := repo.GetProfiles()
+	if err != nil {
+		return err
+	}
+	active, err := repo.GetProfile()
+	if err != nil {
+		return err
+	}
+
+	if len(profiles) == 0 {
+		fmt.Fprintln(out, "No profiles saved.")
+		return nil
+	}
+
+	for _, p := range profiles {
+		marker := " "
+		if active != nil && p.ID == active.ID {
+			marker = "*"
+		}
+		fmt.Fprintf(out, "%s %3d  %-24s %s\n", marker, p.ID, p.FirstName+" "+p.LastName, p.Type)
+	}
+	return nil
+}
+
+func listTimesheets(repo *db.Repository, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	fs.SetOutput(out)
+	profileID := fs.Int64("profile", 0, "profile id (defaults to the active profile)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	prof, err := loadProfile(repo, *profileID)
+	if err != nil {
+		return err
+	}
+
+	sheets, err := repo.GetTimesheets(prof.ID)
+	if err != nil {
+		return err
+	}
+
+	if len(sheets) == 0 {
+		fmt.Fprintln(out, "No timesheets saved.")
+		return nil
+	}
+
+	fmt.Fprintf(out, "%-22s %10s %10s  %s\n", "PERIOD", "WORKED", "OVERTIME", "STATUS")
+	for _, t := range sheets {
+		fmt.Fprintf(out, "%-22s %10.2f %10.2f  %s\n", periodArg(t.Period()), t.TotalWorked, t.TotalOvertime, t.Status.Label())
+	}
+	return nil
+}
+
+func showTimesheet(repo *db.Repository, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("show", flag.ContinueOnError)
+	fs.SetOutput(out)
+	profileID := fs.Int64("profile", 0, "profile id (defaults to the active profile)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("show needs a month or day, e.g. timesheets show 2026-09")
+	}
+
+	day, err := parseDay(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	prof, err := loadProfile(repo, *profileID)
+	if err != nil {
+		return err
+	}
+
+	ts, err := loadTimesheet(repo, prof, day)
+	if err != nil {
+		return err
+	}
+
+	// Name and employee type as they were when the sheet was saved
+	report := ts.ReportProfile(prof)
+	fmt.Fprintf(out, "%s %s - %s\n", report.FirstName, report.LastName, ts.Period().Label())
+
+	// Print days in calendar order
+	dates := make([]string, 0, len(ts.Entries))
+	for date, entry := range ts.Entries {
+		if entry.TotalPaid() > 0 {
+			dates = append(dates, date)
+		}
+	}
+	sort.Strings(dates)
+
+	for _, date := range dates {
+		e := ts.Entries[date]
+		line := fmt.Sprintf("  %s  worked %5.2f", date, e.HoursWorked)
+		if report.Type == models.TypeFullTime {
+			line += fmt.Sprintf("  sick %5.2f  vac %5.2f  hol %5.2f  comp %5.2f  other %5.2f",
+				e.SickLeave, e.Vacation, e.Holiday, e.CompTimeTaken, e.OtherPaid)
+		}
+		if e.SecondaryHours > 0 {
+			line += fmt.Sprintf("  (2nd FOAP %.2f)", e.SecondaryHours)
+		}
+		if e.Closure != "" {
+			line += "  (" + e.Closure + ")"
+		}
+		if len(e.Punches) > 0 {
+			line += "  punches " + e.PunchString()
+		}
+		fmt.Fprintln(out, line)
+	}
+
+	fmt.Fprintf(out, "Status:         %s\n", ts.Status.Label())
+	if ts.ReviewerComment != "" {
+		fmt.Fprintf(out, "Reviewer:       %s\n", ts.ReviewerComment)
+	}
+	fmt.Fprintf(out, "Total worked:   %.2f\n", ts.TotalWorked)
+	fmt.Fprintf(out, "Total overtime: %.2f\n", ts.TotalOvertime)
+	switch report.Type {
+	case models.TypeFullTime:
+		fmt.Fprintf(out, "Comp earned:    %.2f\n", ts.CompTimeEarned)
+	case models.TypeWorkStudy:
+		fmt.Fprintf(out, "New balance:    %.2f\n", ts.NewBalance)
+	}
+
+	earnings := rules.EstimateEarnings(report, ts, rules.PolicyFor(report.Type))
+	fmt.Fprintf(out, "Regular pay:    $%.2f\n", earnings.RegularPay)
+	if earnings.CompBanked {
+		fmt.Fprintf(out, "Overtime pay:   banked as comp time\n")
+	} else {
+		fmt.Fprintf(out, "Overtime pay:   $%.2f\n", earnings.OvertimePay)
+	}
+	fmt.Fprintf(out, "Gross earnings: $%.2f\n", earnings.Gross)
+	return nil
+}
+
+func exportTimesheet(repo *db.Repository, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.SetOutput(out)
+	profileID := fs.Int64("profile", 0, "profile id (defaults to the active profile)")
+	monthFlag := fs.String("month", "", "month (YYYY-MM) or day (YYYY-MM-DD) whose timesheet to export")
+	outPath := fs.String("out", "", "output PDF path (defaults to timesheet_<Month>_<Year>.pdf or timesheet_<start>_to_<end>.pdf)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *monthFlag == "" {
+		return errors.New("export needs --month YYYY-MM or YYYY-MM-DD")
+	}
+
+	day, err := parseDay(*monthFlag)
+	if err != nil {
+		return err
+	}
+
+	prof, err := loadProfile(repo, *profileID)
+	if err != nil {
+		return err
+	}
+
+	ts, err := loadTimesheet(repo, prof, day)
+	if err != nil {
+		return err
+	}
+
+	// Same default name as the desktop export dialog
+	path := *outPath
+	if path == "" {
+		path = fmt.Sprintf("timesheet_%s.pdf", ts.Period().FileLabel())
+	}
+
+	if err := pdfgen.GenerateTimesheet(prof, ts, path); err != nil {
+		return fmt.Errorf("failed to generate PDF: %w", err)
+	}
+
+	fmt.Fprintf(out, "Wrote %s\n", path)
+	return nil
+}
+
+// Resolve --profile, falling back to the active profile
+func loadProfile(repo *db.Repository, id int64) (*models.Profile, error) {
+	var prof *models.Profile
+	var err error
+	if id != 0 {
+		prof, err = repo.GetProfileByID(id)
+	} else {
+		prof, err = repo.GetProfile()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if prof == nil {
+		if id != 0 {
+			return nil, fmt.Errorf("no profile with id %d", id)
+		}
+		return nil, errors.New("no profile saved, create one in the desktop app first")
+	}
+	return prof, nil
+}
+
+// Load the saved sheet holding a day and fill in derived fields the same way the Calendar tab does
+func loadTimesheet(repo *db.Repository, prof *models.Profile, day time.Time) (*models.Timesheet, error) {
+	ts, err := repo.GetTimesheetOn(prof.ID, day)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load timesheet: %w", err)
+	}
+	if ts == nil {
+		return nil, fmt.Errorf("no timesheet saved for %s", day.Format("Jan 2, 2006"))
+	}
+
+	ts.CarryIn, err = repo.GetCarryInEntries(prof.ID, ts.Period().Start)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load previous timesheet: %w", err)
+	}
+
+	if report := ts.ReportProfile(prof); report.Type == models.TypeWorkStudy {
+		if err := repo.ApplyWorkStudyBalance(report, ts); err != nil {
+			return nil, fmt.Errorf("failed to calculate balance: %w", err)
+		}
+	}
+	return ts, nil
+}
+
+// Parse a YYYY-MM month (its 1st) or YYYY-MM-DD day argument
+func parseDay(s string) (time.Time, error) {
+	if day, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return day, nil
+	}
+	month, err := time.ParseInLocation("2006-01", s, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, expected YYYY-MM or YYYY-MM-DD", s)
+	}
+	return month, nil
+}
+
+// Period as typed on the command line: "2026-09" for a month, otherwise its first and last day
+func periodArg(p models.Period) string {
+	if p.IsMonth() {
+		return p.Start.Format("2006-01")
+	}
+	return p.Key() + ".." + p.EndKey()
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"calendar_utility_node_for_timesheets/cli"
+	"calendar_utility_node_for_timesheets/db"
+)
+
+// Headless timesheets commands. This binary does not import gui or Fyne, so it builds
+// without cgo and runs with no display server: over SSH, in cron and in CI
+func main() {
+	repo, err := db.OpenDefault()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := cli.Run(repo, os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
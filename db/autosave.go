@@ -0,0 +1,100 @@
+package db
+
+import (
+	"sync"
+	"time"
+
+	"calendar_utility_node_for_timesheets/models"
+)
+
+// AutoSaveDelay is how long typing has to pause before a draft is journaled
+const AutoSaveDelay = 2 * time.Second
+
+// AutoSaver debounces journal writes. Every Queue restarts the delay, so a burst of
+// keystrokes costs one write once the user pauses.
+type AutoSaver struct {
+	repo  *Repository
+	delay time.Duration
+
+	// OnError is told when a timed write fails. It runs on the timer's goroutine
+	OnError func(error)
+
+	// schedule runs f after d and returns a func stopping it. time.AfterFunc unless a
+	// test swaps in its own clock
+	schedule func(d time.Duration, f func()) (stop func() bool)
+
+	// mu is held across the journal write, so once Cancel returns no older draft can
+	// still land on top of a save
+	mu        sync.Mutex
+	stopTimer func() bool
+	pending   *models.JournalEntry
+	gen       uint64 // Bumped by Queue and Cancel, a timer from an older one does nothing
+}
+
+// NewAutoSaver creates a debouncer writing to repo after delay
+func NewAutoSaver(repo *Repository, delay time.Duration) *AutoSaver {
+	return &AutoSaver{repo: repo, delay: delay, schedule: afterFunc}
+}
+
+// Helper scheduling f on a real timer
+func afterFunc(d time.Duration, f func()) func() bool {
+	return time.AfterFunc(d, f).Stop
+}
+
+// Queue replaces the pending draft and restarts the delay
+func (a *AutoSaver) Queue(j models.JournalEntry) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.stop()
+	a.pending = &j
+	gen := a.gen
+	a.stopTimer = a.schedule(a.delay, func() {
+		if err := a.flush(gen); err != nil && a.OnError != nil {
+			a.OnError(err)
+		}
+	})
+}
+
+// Flush writes the pending draft now, if there is one
+func (a *AutoSaver) Flush() error {
+	a.mu.Lock()
+	gen := a.gen
+	a.mu.Unlock()
+	return a.flush(gen)
+}
+
+// Cancel drops the pending draft without writing it. A write already under way
+// finishes first, so the journal can be cleared safely once Cancel returns
+func (a *AutoSaver) Cancel() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.stop()
+	a.pending = nil
+}
+
+// Helper writing the pending draft if nothing was queued or cancelled since gen
+func (a *AutoSaver) flush(gen uint64) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if gen != a.gen || a.pending == nil {
+		return nil
+	}
+	j := *a.pending
+	a.stop()
+	a.pending = nil
+
+	j.SavedAt = time.Now()
+	return a.repo.WriteJournal(j)
+}
+
+// Helper stopping the timer and starting a new generation. Callers hold mu
+func (a *AutoSaver) stop() {
+	a.gen++
+	if a.stopTimer != nil {
+		a.stopTimer()
+		a.stopTimer = nil
+	}
+}
@@ -0,0 +1,145 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"calendar_utility_node_for_timesheets/models"
+)
+
+// fakeClock stands in for time.AfterFunc, so a test decides when each timer fires
+type fakeClock struct {
+	delays []time.Duration
+	fires  []func()
+}
+
+// Helper recording a timer instead of starting it
+func (c *fakeClock) schedule(d time.Duration, f func()) func() bool {
+	c.delays = append(c.delays, d)
+	c.fires = append(c.fires, f)
+	return func() bool { return true }
+}
+
+// Helper firing every recorded timer in order, stopped or not, as a late timer would
+func (c *fakeClock) fireAll() {
+	for _, f := range c.fires {
+		f()
+	}
+}
+
+func TestAutoSaver(t *testing.T) {
+	period := models.MonthPeriod(2026, 3)
+	draft := models.JournalEntry{
+		ProfileID:   1,
+		PeriodStart: period.Key(),
+		PeriodEnd:   period.EndKey(),
+		Entries:     map[string]models.DailyEntry{"2026-03-02": {Date: "2026-03-02", HoursWorked: 4}},
+	}
+
+	tests := []struct {
+		name  string
+		after func(a *AutoSaver) error // Run right after the draft is queued
+		want  bool                     // Draft journaled once the timers have fired
+	}{
+		{"timer writes", func(*AutoSaver) error { return nil }, true},
+		{"flush writes now", func(a *AutoSaver) error { return a.Flush() }, true},
+		{"cancel drops", func(a *AutoSaver) error { a.Cancel(); return nil }, false},
+		{"flush then cancel", func(a *AutoSaver) error {
+			if err := a.Flush(); err != nil {
+				return err
+			}
+			a.Cancel()
+			return nil
+		}, true},
+		{"requeue writes once", func(a *AutoSaver) error { a.Queue(draft); return nil }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := NewRepository(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer repo.Conn.Close()
+
+			clock := &fakeClock{}
+			a := NewAutoSaver(repo, AutoSaveDelay)
+			a.schedule = clock.schedule
+			a.Queue(draft)
+			if err := tt.after(a); err != nil {
+				t.Fatal(err)
+			}
+			clock.fireAll()
+
+			j, err := repo.GetJournal(1, period)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := j != nil; got != tt.want {
+				t.Errorf("journaled = %v, want %v", got, tt.want)
+			}
+			for _, d := range clock.delays {
+				if d != AutoSaveDelay {
+					t.Errorf("timer delay = %v, want %v", d, AutoSaveDelay)
+				}
+			}
+		})
+	}
+}
+
+func TestAutoSaverStaleTimer(t *testing.T) {
+	repo, err := NewRepository(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Conn.Close()
+
+	period := models.MonthPeriod(2026, 3)
+	first := models.JournalEntry{ProfileID: 1, PeriodStart: period.Key(), PeriodEnd: period.EndKey(),
+		Entries: map[string]models.DailyEntry{"2026-03-02": {Date: "2026-03-02", HoursWorked: 4}}}
+	second := first
+	second.Entries = map[string]models.DailyEntry{"2026-03-02": {Date: "2026-03-02", HoursWorked: 6}}
+
+	clock := &fakeClock{}
+	a := NewAutoSaver(repo, AutoSaveDelay)
+	a.schedule = clock.schedule
+	a.Queue(first)
+	a.Queue(second)
+
+	// The first timer was replaced, so firing it late writes nothing
+	clock.fires[0]()
+	if j, err := repo.GetJournal(1, period); err != nil || j != nil {
+		t.Fatalf("journal after stale timer = %v, %v, want nothing", j, err)
+	}
+
+	clock.fires[1]()
+	j, err := repo.GetJournal(1, period)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if j == nil || j.Entries["2026-03-02"].HoursWorked != 6 {
+		t.Errorf("journal = %+v, want the second draft", j)
+	}
+}
+
+func TestAutoSaverOnError(t *testing.T) {
+	repo, err := NewRepository(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clock := &fakeClock{}
+	a := NewAutoSaver(repo, AutoSaveDelay)
+	a.schedule = clock.schedule
+	var got error
+	a.OnError = func(err error) { got = err }
+
+	period := models.MonthPeriod(2026, 3)
+	a.Queue(models.JournalEntry{ProfileID: 1, PeriodStart: period.Key(), PeriodEnd: period.EndKey()})
+	repo.Conn.Close()
+	clock.fireAll()
+
+	if got == nil {
+		t.Error("OnError was not told about the failed write")
+	}
+}
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"calendar_utility_node_for_timesheets/models"
+	"calendar_utility_node_for_timesheets/rules"
+)
+
+func TestLeaveLedgerCompAcrossMonths(t *testing.T) {
+	tests := []struct {
+		name      string
+		overtime  []string   // Weekdays worked 9 hours, 5 of them make a week's overtime
+		wantMonth [2]float64 // Comp earned on the March and April ledger lines
+	}{
+		{"week ending in march", []string{"2026-03-23", "2026-03-24", "2026-03-25", "2026-03-26", "2026-03-27"}, [2]float64{7.5, 0}},
+		{"week ending in april", []string{"2026-03-30", "2026-03-31", "2026-04-01", "2026-04-02", "2026-04-03"}, [2]float64{0, 7.5}},
+		{"one week each", []string{
+			"2026-03-23", "2026-03-24", "2026-03-25", "2026-03-26", "2026-03-27",
+			"2026-03-30", "2026-03-31", "2026-04-01", "2026-04-02", "2026-04-03",
+		}, [2]float64{7.5, 7.5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := NewRepository(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer repo.Conn.Close()
+
+			p := models.Profile{FirstName: "Ada", Type: models.TypeFullTime}
+			if err := repo.SaveProfile(&p); err != nil {
+				t.Fatal(err)
+			}
+			account := models.LeaveAccount{ProfileID: p.ID, Type: models.LeaveComp, StartMonth: 3, StartYear: 2026}
+			if err := repo.SaveLeaveAccount(account); err != nil {
+				t.Fatal(err)
+			}
+
+			// A biweekly sheet from Monday the 23rd of March to Sunday the 5th of April
+			period, err := models.ParsePeriod("2026-03-23", "2026-04-05")
+			if err != nil {
+				t.Fatal(err)
+			}
+			ts := models.Timesheet{ProfileID: p.ID, Entries: map[string]models.DailyEntry{}}
+			ts.SetPeriod(period)
+			for _, date := range tt.overtime {
+				ts.Entries[date] = models.DailyEntry{Date: date, HoursWorked: 9}
+			}
+			rules.Summarize(&ts, rules.DefaultPolicy(models.TypeFullTime))
+			if err := repo.SaveTimesheet(ts); err != nil {
+				t.Fatal(err)
+			}
+
+			ledger, err := repo.GetLeaveLedger(p.ID, models.LeaveComp, int(time.April), 2026)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(ledger) != 2 {
+				t.Fatalf("ledger lines = %d, want 2", len(ledger))
+			}
+			for i, line := range ledger {
+				if line.Earned != tt.wantMonth[i] {
+					t.Errorf("%d/%d earned = %v, want %v", line.Month, line.Year, line.Earned, tt.wantMonth[i])
+				}
+			}
+		})
+	}
+}
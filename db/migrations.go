@@ -0,0 +1,386 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+// ErrSchemaTooNew is returned when the database was written by a newer build of the app
+var ErrSchemaTooNew = errors.New("database schema is newer than this version of the app")
+
+// migration is one ordered schema upgrade step. Version is the PRAGMA user_version the
+// database reaches once the step commits.
+type migration struct {
+	Version int
+	Name    string
+
+	// Destructive steps rebuild or drop data, the DB file is backed up before migrating
+	// when any pending step is destructive
+	Destructive bool
+
+	Apply func(tx *sql.Tx) error
+}
+
+// migrations lists every schema change in order. Never edit a released step, append a new one.
+var migrations = []migration{
+	{
+		Version: 1,
+		Name:    "timesheet summary fields",
+		Apply: execSteps(
+			`ALTER TABLE timesheets ADD COLUMN weeks_json TEXT`, //[]WeeklyEntry
+			`ALTER TABLE timesheets ADD COLUMN total_overtime REAL DEFAULT 0`,
+			`ALTER TABLE timesheets ADD COLUMN comp_time_earned REAL DEFAULT 0`,
+			`ALTER TABLE timesheets ADD COLUMN other_paid_description TEXT DEFAULT ''`,
+			`ALTER TABLE timesheets ADD COLUMN gross_earnings REAL DEFAULT 0`,
+			`ALTER TABLE timesheets ADD COLUMN current_balance REAL DEFAULT 0`,
+			`ALTER TABLE timesheets ADD COLUMN new_balance REAL DEFAULT 0`,
+		),
+	},
+	{
+		Version:     2,
+		Name:        "multiple profiles",
+		Destructive: true,
+		Apply: execSteps(
+			// Drop the single-row CHECK on profile
+			`CREATE TABLE profile_v2 (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				first_name TEXT,
+				last_name TEXT,
+				data_json TEXT --store rest of data as JSON blob
+			)`,
+			`INSERT INTO profile_v2 (id, first_name, last_name, data_json)
+				SELECT id, first_name, last_name, data_json FROM profile`,
+			`DROP TABLE profile`,
+			`ALTER TABLE profile_v2 RENAME TO profile`,
+
+			// Key timesheets by profile. Existing sheets belong to the original profile (id 1).
+			`CREATE TABLE timesheets_v2 (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				profile_id INTEGER NOT NULL DEFAULT 1 REFERENCES profile(id) ON DELETE CASCADE,
+				month INTEGER,
+				year INTEGER,
+				total_worked REAL,
+				entries_json TEXT, --map[string]DailyEntry
+				weeks_json TEXT, --[]WeeklyEntry
+				total_overtime REAL DEFAULT 0,
+				comp_time_earned REAL DEFAULT 0,
+				other_paid_description TEXT DEFAULT '',
+				gross_earnings REAL DEFAULT 0,
+				current_balance REAL DEFAULT 0,
+				new_balance REAL DEFAULT 0,
+				UNIQUE (profile_id, month, year) -- One sheet per job per month
+			)`,
+			`INSERT INTO timesheets_v2 (id, profile_id, month, year, total_worked, entries_json, weeks_json,
+				total_overtime, comp_time_earned, other_paid_description, gross_earnings, current_balance, new_balance)
+				SELECT id, 1, month, year, total_worked, entries_json, weeks_json,
+				total_overtime, comp_time_earned, other_paid_description, gross_earnings, current_balance, new_balance
+				FROM timesheets`,
+			`DROP TABLE timesheets`,
+			`ALTER TABLE timesheets_v2 RENAME TO timesheets`,
+
+			// App-wide key/value settings (active profile, etc.)
+			`CREATE TABLE settings (
+				key TEXT PRIMARY KEY,
+				value TEXT
+			)`,
+		),
+	},
+	{
+		Version: 3,
+		Name:    "holiday calendar",
+		Apply: execSteps(
+			// College-wide holidays and closures, shared by every profile
+			`CREATE TABLE holidays (
+				date TEXT PRIMARY KEY, --2006-01-02
+				name TEXT NOT NULL
+			)`,
+		),
+	},
+	{
+		Version: 4,
+		Name:    "leave ledger",
+		Apply: execSteps(
+			// Starting balance and monthly accrual per profile and leave type.
+			// Usage and comp earned are read from the saved timesheets.
+			`CREATE TABLE leave_accounts (
+				profile_id INTEGER NOT NULL REFERENCES profile(id) ON DELETE CASCADE,
+				leave_type TEXT NOT NULL, --sick, vacation, comp
+				starting_balance REAL DEFAULT 0,
+				accrual_rate REAL DEFAULT 0, --hours per month
+				start_month INTEGER,
+				start_year INTEGER,
+				PRIMARY KEY (profile_id, leave_type)
+			)`,
+		),
+	},
+	{
+		Version: 5,
+		Name:    "timesheet approval status",
+		Apply: execSteps(
+			`ALTER TABLE timesheets ADD COLUMN status TEXT DEFAULT 'draft'`,
+			`ALTER TABLE timesheets ADD COLUMN submitted_at TEXT DEFAULT ''`, //RFC3339
+			`ALTER TABLE timesheets ADD COLUMN reviewed_at TEXT DEFAULT ''`,
+			`ALTER TABLE timesheets ADD COLUMN reviewer_comment TEXT DEFAULT ''`,
+			`ALTER TABLE timesheets ADD COLUMN reopened_at TEXT DEFAULT ''`,
+			`ALTER TABLE timesheets ADD COLUMN reopen_reason TEXT DEFAULT ''`,
+		),
+	},
+	{
+		Version: 6,
+		Name:    "revision history",
+		Apply: execSteps(
+			// One row per timesheet save or status change
+			`CREATE TABLE timesheet_revisions (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				profile_id INTEGER NOT NULL,
+				month INTEGER NOT NULL,
+				year INTEGER NOT NULL,
+				saved_at TEXT NOT NULL, --RFC3339
+				status TEXT,
+				note TEXT DEFAULT '',
+				entries_json TEXT, --map[string]DailyEntry after the save
+				changes_json TEXT --[]DayChange against the previous save
+			)`,
+			`CREATE INDEX timesheet_revisions_month ON timesheet_revisions (profile_id, year, month)`,
+
+			// One row per profile save
+			`CREATE TABLE profile_revisions (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				profile_id INTEGER NOT NULL,
+				saved_at TEXT NOT NULL,
+				data_json TEXT
+			)`,
+
+			// History is append-only
+			`CREATE TRIGGER timesheet_revisions_no_update BEFORE UPDATE ON timesheet_revisions
+				BEGIN SELECT RAISE(ABORT, 'timesheet revisions are append-only'); END`,
+			`CREATE TRIGGER timesheet_revisions_no_delete BEFORE DELETE ON timesheet_revisions
+				BEGIN SELECT RAISE(ABORT, 'timesheet revisions are append-only'); END`,
+			`CREATE TRIGGER profile_revisions_no_update BEFORE UPDATE ON profile_revisions
+				BEGIN SELECT RAISE(ABORT, 'profile revisions are append-only'); END`,
+			`CREATE TRIGGER profile_revisions_no_delete BEFORE DELETE ON profile_revisions
+				BEGIN SELECT RAISE(ABORT, 'profile revisions are append-only'); END`,
+		),
+	},
+	{
+		Version: 7,
+		Name:    "profile snapshot on timesheets",
+		Apply: execSteps(
+			`ALTER TABLE timesheets ADD COLUMN profile_json TEXT`, //Profile as of the last save
+		),
+	},
+	{
+		Version:     8,
+		Name:        "pay periods",
+		Destructive: true,
+		Apply: execSteps(
+			// Key timesheets by the first day of their pay period. Month and year stay as
+			// the month the period starts in; existing sheets cover their calendar month.
+			`CREATE TABLE timesheets_v8 (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				profile_id INTEGER NOT NULL DEFAULT 1 REFERENCES profile(id) ON DELETE CASCADE,
+				month INTEGER,
+				year INTEGER,
+				period_start TEXT NOT NULL, --2006-01-02
+				period_end TEXT NOT NULL,
+				total_worked REAL,
+				entries_json TEXT, --map[string]DailyEntry
+				weeks_json TEXT, --[]WeeklyEntry
+				total_overtime REAL DEFAULT 0,
+				comp_time_earned REAL DEFAULT 0,
+				other_paid_description TEXT DEFAULT '',
+				gross_earnings REAL DEFAULT 0,
+				current_balance REAL DEFAULT 0,
+				new_balance REAL DEFAULT 0,
+				status TEXT DEFAULT 'draft',
+				submitted_at TEXT DEFAULT '',
+				reviewed_at TEXT DEFAULT '',
+				reviewer_comment TEXT DEFAULT '',
+				reopened_at TEXT DEFAULT '',
+				reopen_reason TEXT DEFAULT '',
+				profile_json TEXT,
+				UNIQUE (profile_id, period_start) -- One sheet per job per pay period
+			)`,
+			`INSERT INTO timesheets_v8 (id, profile_id, month, year, period_start, period_end, total_worked,
+				entries_json, weeks_json, total_overtime, comp_time_earned, other_paid_description, gross_earnings,
+				current_balance, new_balance, status, submitted_at, reviewed_at, reviewer_comment, reopened_at,
+				reopen_reason, profile_json)
+				SELECT id, profile_id, month, year,
+				printf('%04d-%02d-01', year, month),
+				date(printf('%04d-%02d-01', year, month), '+1 month', '-1 day'),
+				total_worked, entries_json, weeks_json, total_overtime, comp_time_earned, other_paid_description,
+				gross_earnings, current_balance, new_balance, status, submitted_at, reviewed_at, reviewer_comment,
+				reopened_at, reopen_reason, profile_json
+				FROM timesheets`,
+			`DROP TABLE timesheets`,
+			`ALTER TABLE timesheets_v8 RENAME TO timesheets`,
+
+			// Revisions follow the same key. The backfill is the only edit history ever gets
+			`ALTER TABLE timesheet_revisions ADD COLUMN period_start TEXT DEFAULT ''`,
+			`DROP TRIGGER timesheet_revisions_no_update`,
+			`UPDATE timesheet_revisions SET period_start = printf('%04d-%02d-01', year, month)`,
+			`CREATE TRIGGER timesheet_revisions_no_update BEFORE UPDATE ON timesheet_revisions
+				BEGIN SELECT RAISE(ABORT, 'timesheet revisions are append-only'); END`,
+			`CREATE INDEX timesheet_revisions_period ON timesheet_revisions (profile_id, period_start)`,
+		),
+	},
+	{
+		Version: 9,
+		Name:    "auto-save journal",
+		Apply: execSteps(
+			// Unsaved Calendar edits, one row per sheet. Cleared on save or discard
+			`CREATE TABLE timesheet_journal (
+				profile_id INTEGER NOT NULL REFERENCES profile(id) ON DELETE CASCADE,
+				period_start TEXT NOT NULL, --2006-01-02
+				period_end TEXT NOT NULL,
+				saved_at TEXT NOT NULL, --RFC3339
+				entries_json TEXT, --map[string]DailyEntry
+				other_paid_description TEXT DEFAULT '',
+				PRIMARY KEY (profile_id, period_start)
+			)`,
+		),
+	},
+}
+
+// schemaVersion is the schema revision this build expects
+var schemaVersion = migrations[len(migrations)-1].Version
+
+// Version 0 schema, the tables every database started with
+var baseSchema = []string{
+	// Profile table. Schedule stored as JSON blob.
+	`CREATE TABLE IF NOT EXISTS profile (
+		id INTEGER PRIMARY KEY CHECK (id = 1), --ensure single row for 1 profile
+		first_name TEXT,
+		last_name TEXT,
+		data_json TEXT --store rest of data as JSON blob
+	);`,
+
+	//Timesheet table. Daily entries stored as JSON blob.
+	`CREATE TABLE IF NOT EXISTS timesheets (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		month INTEGER,
+		year INTEGER,
+		total_worked REAL,
+		entries_json TEXT, --map[string]DailyEntry
+		UNIQUE (month, year) -- Prevent duplicate sheets for the same month
+	);`,
+}
+
+// migrate upgrades the database at dbPath to schemaVersion
+func migrate(conn *sql.DB, dbPath string) error {
+	version, err := userVersion(conn)
+	if err != nil {
+		return err
+	}
+
+	if version > schemaVersion {
+		return fmt.Errorf("%w (database v%d, app v%d)", ErrSchemaTooNew, version, schemaVersion)
+	}
+
+	// Fresh or pre-versioning database
+	fresh := false
+	if version == 0 {
+		var tables int
+		if err := conn.QueryRow(`SELECT count(*) FROM sqlite_master WHERE type = 'table'`).Scan(&tables); err != nil {
+			return err
+		}
+		fresh = tables == 0
+
+		for _, stmt := range baseSchema {
+			if _, err := conn.Exec(stmt); err != nil {
+				return fmt.Errorf("base schema: %w", err)
+			}
+		}
+	}
+
+	// Back up the file as the user had it before any step runs when a pending step is
+	// destructive. Nothing to lose on a brand new file
+	if !fresh {
+		for _, m := range migrations {
+			if m.Version <= version || !m.Destructive {
+				continue
+			}
+			backup, err := backupDatabase(conn, dbPath, version)
+			if err != nil {
+				return fmt.Errorf("backup before v%d: %w", m.Version, err)
+			}
+			log.Printf("Backed up database to %s before migrating from v%d", backup, version)
+			break
+		}
+	}
+
+	for _, m := range migrations {
+		if m.Version <= version {
+			continue
+		}
+
+		if err := applyMigration(conn, m); err != nil {
+			return fmt.Errorf("v%d %s: %w", m.Version, m.Name, err)
+		}
+		version = m.Version
+	}
+
+	return nil
+}
+
+// applyMigration runs a single step and bumps user_version in the same transaction
+func applyMigration(conn *sql.DB, m migration) error {
+	tx, err := conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := m.Apply(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// PRAGMA does not accept bound parameters
+	if _, err := tx.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, m.Version)); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// backupDatabase writes a consistent copy of the database next to the original
+func backupDatabase(conn *sql.DB, dbPath string, version int) (string, error) {
+	backupPath := fmt.Sprintf("%s.v%d.bak", dbPath, version)
+
+	// VACUUM INTO refuses to overwrite, keep the newest backup for this version
+	if err := os.Remove(backupPath); err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	quoted := strings.ReplaceAll(backupPath, "'", "''")
+	if _, err := conn.Exec(fmt.Sprintf(`VACUUM INTO '%s'`, quoted)); err != nil {
+		return "", err
+	}
+	return backupPath, nil
+}
+
+// userVersion reads the schema revision stored in the database header
+func userVersion(conn *sql.DB) (int, error) {
+	var version int
+	if err := conn.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+// execSteps builds a migration body from plain SQL statements
+func execSteps(stmts ...string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		for _, stmt := range stmts {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,216 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"calendar_utility_node_for_timesheets/models"
+)
+
+// Helper building a database as an older build of the app left it: the version 0 tables
+// with one profile and one March 2026 sheet, upgraded by the released steps up to version
+func writeFixture(t *testing.T, dir string, version int) string {
+	t.Helper()
+	dbPath := filepath.Join(dir, "school_timesheets.db")
+
+	conn, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	for _, stmt := range baseSchema {
+		if _, err := conn.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fixture := []string{
+		`INSERT INTO profile (id, first_name, last_name, data_json)
+			VALUES (1, 'Ada', 'Lovelace', '{"first_name":"Ada","last_name":"Lovelace","type":"Part-Time","rate":12.5}')`,
+		`INSERT INTO timesheets (month, year, total_worked, entries_json)
+			VALUES (3, 2026, 4, '{"2026-03-02":{"date":"2026-03-02","hours_worked":4}}')`,
+	}
+	for _, stmt := range fixture {
+		if _, err := conn.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, m := range migrations {
+		if m.Version > version {
+			break
+		}
+		if err := applyMigration(conn, m); err != nil {
+			t.Fatalf("fixture v%d: %v", m.Version, err)
+		}
+	}
+	return dbPath
+}
+
+// Helper checking that the fixture's data made it to the current schema
+func checkUpgraded(t *testing.T, repo *Repository) {
+	t.Helper()
+
+	version, err := userVersion(repo.Conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != schemaVersion {
+		t.Fatalf("user_version = %d, want %d", version, schemaVersion)
+	}
+
+	for _, table := range []string{"profile", "timesheets", "settings", "holidays", "leave_accounts",
+		"timesheet_revisions", "profile_revisions", "timesheet_journal"} {
+		var n int
+		if err := repo.Conn.QueryRow(`SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, table).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		if n != 1 {
+			t.Errorf("table %s missing", table)
+		}
+	}
+
+	p, err := repo.GetProfileByID(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p == nil || p.FirstName != "Ada" || p.Type != models.TypePartTime {
+		t.Fatalf("profile = %+v", p)
+	}
+
+	ts, err := repo.GetTimesheetForPeriod(1, models.MonthPeriod(2026, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ts == nil {
+		t.Fatal("March 2026 sheet missing")
+	}
+	if ts.PeriodStart != "2026-03-01" || ts.PeriodEnd != "2026-03-31" {
+		t.Errorf("period = %s to %s, want 2026-03-01 to 2026-03-31", ts.PeriodStart, ts.PeriodEnd)
+	}
+	if ts.Status != models.StatusDraft {
+		t.Errorf("status = %q, want draft", ts.Status)
+	}
+	if got := ts.Entries["2026-03-02"].HoursWorked; got != 4 {
+		t.Errorf("2026-03-02 hours = %v, want 4", got)
+	}
+}
+
+func TestMigrateFromEveryVersion(t *testing.T) {
+	for version := 0; version < schemaVersion; version++ {
+		t.Run(fmt.Sprintf("v%d", version), func(t *testing.T) {
+			dir := t.TempDir()
+			dbPath := writeFixture(t, dir, version)
+
+			repo, err := NewRepository(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer repo.Conn.Close()
+			checkUpgraded(t, repo)
+
+			// A pending destructive step backs up the file as it was before any step ran
+			destructive := false
+			for _, m := range migrations {
+				destructive = destructive || (m.Version > version && m.Destructive)
+			}
+			backupPath := fmt.Sprintf("%s.v%d.bak", dbPath, version)
+			if _, err := os.Stat(backupPath); destructive != (err == nil) {
+				t.Fatalf("backup %s exists = %v, want %v", backupPath, err == nil, destructive)
+			}
+			if !destructive {
+				return
+			}
+
+			backup, err := sql.Open("sqlite", backupPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer backup.Close()
+			got, err := userVersion(backup)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != version {
+				t.Errorf("backup user_version = %d, want %d", got, version)
+			}
+		})
+	}
+}
+
+func TestMigrateFreshDatabase(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := NewRepository(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Conn.Close()
+
+	version, err := userVersion(repo.Conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != schemaVersion {
+		t.Fatalf("user_version = %d, want %d", version, schemaVersion)
+	}
+
+	// Nothing to back up on a new file
+	backups, _ := filepath.Glob(filepath.Join(dir, "*.bak"))
+	if len(backups) != 0 {
+		t.Errorf("unexpected backups %v", backups)
+	}
+}
+
+func TestReopenUpgradedDatabase(t *testing.T) {
+	dir := t.TempDir()
+	writeFixture(t, dir, 0)
+
+	repo, err := NewRepository(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo.Conn.Close()
+
+	// Reopening runs no step, so no new backup is taken
+	backups, _ := filepath.Glob(filepath.Join(dir, "*.bak"))
+	for _, b := range backups {
+		if err := os.Remove(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	repo, err = NewRepository(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Conn.Close()
+	checkUpgraded(t, repo)
+
+	backups, _ = filepath.Glob(filepath.Join(dir, "*.bak"))
+	if len(backups) != 0 {
+		t.Errorf("reopen took backups %v", backups)
+	}
+}
+
+func TestRejectNewerSchema(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := writeFixture(t, dir, schemaVersion)
+
+	conn, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, schemaVersion+1)); err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+
+	_, err = NewRepository(dir)
+	if !errors.Is(err, ErrSchemaTooNew) {
+		t.Fatalf("err = %v, want ErrSchemaTooNew", err)
+	}
+}
@@ -0,0 +1,63 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"calendar_utility_node_for_timesheets/models"
+)
+
+func TestResolvePeriod(t *testing.T) {
+	tests := []struct {
+		name      string
+		saved     []models.Period // Sheets saved before the profile moved to biweekly
+		date      string
+		wantStart string
+		wantEnd   string
+		wantSaved bool
+	}{
+		{"no sheets", nil, "2026-10-01", "2026-09-21", "2026-10-04", false},
+		{"inside saved month", []models.Period{models.MonthPeriod(2026, time.September)}, "2026-09-15", "2026-09-01", "2026-09-30", true},
+		{"clipped after saved month", []models.Period{models.MonthPeriod(2026, time.September)}, "2026-10-01", "2026-10-01", "2026-10-04", false},
+		{"clipped before saved month", []models.Period{models.MonthPeriod(2026, time.October)}, "2026-09-28", "2026-09-21", "2026-09-30", false},
+		{"next period untouched", []models.Period{models.MonthPeriod(2026, time.September)}, "2026-10-05", "2026-10-05", "2026-10-18", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := NewRepository(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer repo.Conn.Close()
+
+			p := models.Profile{FirstName: "Ada", Type: models.TypeFullTime}
+			if err := repo.SaveProfile(&p); err != nil {
+				t.Fatal(err)
+			}
+			for _, period := range tt.saved {
+				ts := models.Timesheet{ProfileID: p.ID, Entries: map[string]models.DailyEntry{}}
+				ts.SetPeriod(period)
+				if err := repo.SaveTimesheet(ts); err != nil {
+					t.Fatal(err)
+				}
+			}
+			p.PayPeriod = models.PayPeriod{Kind: models.PeriodFixed, Anchor: "2026-09-07", LengthDays: 14}
+
+			date, err := time.ParseInLocation("2006-01-02", tt.date, time.Local)
+			if err != nil {
+				t.Fatal(err)
+			}
+			period, saved, err := repo.ResolvePeriod(&p, date)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if period.Key() != tt.wantStart || period.EndKey() != tt.wantEnd {
+				t.Errorf("ResolvePeriod(%s) = %s to %s, want %s to %s", tt.date, period.Key(), period.EndKey(), tt.wantStart, tt.wantEnd)
+			}
+			if got := saved != nil; got != tt.wantSaved {
+				t.Errorf("saved sheet = %v, want %v", got, tt.wantSaved)
+			}
+		})
+	}
+}
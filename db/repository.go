@@ -2,11 +2,17 @@ package db
 
 import (
 	"calendar_utility_node_for_timesheets/models"
+	"calendar_utility_node_for_timesheets/rules"
+	"calendar_utility_node_for_timesheets/validation"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	_ "modernc.org/sqlite"
+	"os"
 	"path/filepath"
+	"strings"
+	"time"
 )
 
 type Repository struct {
@@ -20,59 +26,169 @@ func NewRepository(dbFolder string) (*Repository, error) {
 		return nil, err
 	}
 
-	// Profile table. Schedule stored as JSON blob.
-	profilQuery := `
-	CREATE TABLE IF NOT EXISTS profile (
-		id INTEGER PRIMARY KEY CHECK (id = 1), --ensure single row for 1 profile
-		first_name TEXT,
-		last_name TEXT,
-		data_json TEXT --store rest of data as JSON blob
-	);`
+	// Bring the schema up to date before anything reads from it
+	if err := migrate(conn, dbPath); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("schema migration: %w", err)
+	}
 
-	//Timesheet table. Daily entries stored as JSON blob.
-	timesheetQuery := `
-	CREATE TABLE IF NOT EXISTS timesheets (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		month INTEGER,
-		year INTEGER,
-		total_worked REAL,
-		entries_json TEXT, --map[string]DailyEntry
-		UNIQUE (month, year) -- Prevent duplicate sheets for the same month
-	);`
+	return &Repository{Conn: conn}, nil
+}
 
-	if _, err := conn.Exec(profilQuery); err != nil {
-		return nil, fmt.Errorf("profile table init: %w", err)
-	}
+// OpenDefault opens the shared database in the user's config folder and installs the
+// saved overtime and rounding rules. The desktop app and the CLI both use it
+func OpenDefault() (*Repository, error) {
+	configDir, _ := os.UserConfigDir()
+	appPath := filepath.Join(configDir, "Timesheets")
+	os.MkdirAll(appPath, 0755)
 
-	if _, err := conn.Exec(timesheetQuery); err != nil {
-		return nil, fmt.Errorf("timesheet table init: %w", err)
+	repo, err := NewRepository(appPath)
+	if err != nil {
+		return nil, err
 	}
 
-	return &Repository{Conn: conn}, nil
+	if err := repo.LoadOvertimePolicies(); err != nil {
+		return nil, err
+	}
+	if err := repo.LoadRounding(); err != nil {
+		return nil, err
+	}
+	return repo, nil
 }
 
 /* PROFILE METHODS */
 
+// SaveProfile inserts a new profile when p.ID is 0 (and sets p.ID), otherwise updates it.
+// Every save is also appended to profile_revisions.
 func (r *Repository) SaveProfile(p *models.Profile) error {
+	tx, err := r.Conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if p.ID == 0 {
+		res, err := tx.Exec(`INSERT INTO profile (first_name, last_name) VALUES (?, ?)`, p.FirstName, p.LastName)
+		if err != nil {
+			return err
+		}
+		if p.ID, err = res.LastInsertId(); err != nil {
+			return err
+		}
+	}
+
 	data, err := json.Marshal(p)
 	if err != nil {
 		return err
 	}
 
-	// Upser: Inset or replace
+	// Upsert so imported profiles keep their id
+	query := `INSERT OR REPLACE INTO profile (id, first_name, last_name, data_json) VALUES (?, ?, ?, ?)`
+	if _, err := tx.Exec(query, p.ID, p.FirstName, p.LastName, string(data)); err != nil {
+		return err
+	}
 
-	query := `INSERT OR REPLACE INTO profile (id, first_name, last_name, data_json) VALUES (1, ?, ?, ?)`
+	_, err = tx.Exec(`INSERT INTO profile_revisions (profile_id, saved_at, data_json) VALUES (?, ?, ?)`,
+		p.ID, formatTime(time.Now()), string(data))
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
 
-	_, err = r.Conn.Exec(query, p.FirstName, p.LastName, string(data))
+// GetProfile returns the active profile, falling back to the first one. Nil if none exist.
+func (r *Repository) GetProfile() (*models.Profile, error) {
+	id, err := r.GetActiveProfileID()
+	if err != nil {
+		return nil, err
+	}
+
+	if id != 0 {
+		p, err := r.GetProfileByID(id)
+		if err != nil || p != nil {
+			return p, err
+		}
+	}
+
+	// Active profile missing or unset, use the oldest
+	row := r.Conn.QueryRow(`SELECT id, data_json FROM profile ORDER BY id LIMIT 1`)
+	return scanProfile(row)
+}
+
+func (r *Repository) GetProfileByID(id int64) (*models.Profile, error) {
+	row := r.Conn.QueryRow(`SELECT id, data_json FROM profile WHERE id = ?`, id)
+	return scanProfile(row)
+}
+
+// GetProfiles lists every profile in creation order
+func (r *Repository) GetProfiles() ([]models.Profile, error) {
+	rows, err := r.Conn.Query(`SELECT id, data_json FROM profile ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var profiles []models.Profile
+	for rows.Next() {
+		p, err := scanProfile(rows)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, *p)
+	}
+
+	return profiles, rows.Err()
+}
+
+// DeleteProfile removes a profile with all of its timesheets, leave accounts and unsaved drafts
+func (r *Repository) DeleteProfile(id int64) error {
+	tx, err := r.Conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM timesheets WHERE profile_id = ?`, id); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM leave_accounts WHERE profile_id = ?`, id); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM timesheet_journal WHERE profile_id = ?`, id); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM profile WHERE id = ?`, id); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetActiveProfileID returns the profile selected in the switcher, 0 if none was chosen
+func (r *Repository) GetActiveProfileID() (int64, error) {
+	var id int64
+	err := r.Conn.QueryRow(`SELECT CAST(value AS INTEGER) FROM settings WHERE key = 'active_profile_id'`).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return id, err
+}
+
+func (r *Repository) SetActiveProfileID(id int64) error {
+	_, err := r.Conn.Exec(`INSERT OR REPLACE INTO settings (key, value) VALUES ('active_profile_id', ?)`, id)
 	return err
 }
 
-func (r *Repository) GetProfile() (*models.Profile, error) {
-	row := r.Conn.QueryRow(`SELECT data_json FROM profile WHERE id = 1`)
+// scanProfile reads an (id, data_json) row
+func scanProfile(row rowScanner) (*models.Profile, error) {
+	var id int64
 	var dataStr string
 
 	// Scan the result into dataStr
-	if err := row.Scan(&dataStr); err != nil {
+	if err := row.Scan(&id, &dataStr); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // No profile found
 		}
@@ -85,12 +201,25 @@ func (r *Repository) GetProfile() (*models.Profile, error) {
 		return nil, err
 	}
 
+	// Row id is authoritative over whatever was in the blob
+	p.ID = id
+
 	// Return the populated Profile struct
 	return &p, nil
 }
 
 /* TIMESHEET METHODS */
 
+// Column list shared by timesheet queries, order must match scanTimesheet
+const timesheetColumns = `id, profile_id, month, year, period_start, period_end, total_worked, entries_json, weeks_json, total_overtime,
+	comp_time_earned, other_paid_description, gross_earnings, current_balance, new_balance,
+	status, submitted_at, reviewed_at, reviewer_comment, reopened_at, reopen_reason, profile_json`
+
+// ErrTimesheetLocked is returned when saving hours on a submitted or approved sheet
+var ErrTimesheetLocked = errors.New("timesheet is submitted or approved, reopen it to make changes")
+
+// SaveTimesheet inserts or updates a sheet's hours and totals. The approval status is only
+// written for new sheets, use SetTimesheetStatus to change it.
 func (r *Repository) SaveTimesheet(t models.Timesheet) error {
 	// Marshal entries to JSON
 	entriesData, err := json.Marshal(t.Entries)
@@ -98,21 +227,230 @@ func (r *Repository) SaveTimesheet(t models.Timesheet) error {
 		return err
 	}
 
-	// Insert or update timesheet
+	// Marshal weekly rollups to JSON
+	weeksData, err := json.Marshal(t.Weeks)
+	if err != nil {
+		return err
+	}
+
+	// Profile snapshot, NULL keeps the one already saved
+	var profileData sql.NullString
+	if t.ProfileSnapshot != nil {
+		data, err := json.Marshal(t.ProfileSnapshot)
+		if err != nil {
+			return err
+		}
+		profileData = sql.NullString{String: string(data), Valid: true}
+	}
+
+	status := t.Status
+	if status == "" {
+		status = models.StatusDraft
+	}
+
+	// Sheets built without a period cover their calendar month
+	t.SetPeriod(t.Period())
+
+	tx, err := r.Conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Previous version, for the revision diff
+	previous, prevStatus, err := savedEntries(tx, t.ProfileID, t.PeriodStart)
+	if err != nil {
+		return err
+	}
+	if prevStatus != "" {
+		status = prevStatus
+	}
+
+	// Insert or update timesheet, unless the saved sheet is locked
 	query := `
-	INSERT INTO timesheets (month, year, total_worked, entries_json)
-	VALUES (?, ?, ?, ?)
-	ON CONFLICT(month, year) DO UPDATE SET
+	INSERT INTO timesheets (profile_id, month, year, period_start, period_end, total_worked, entries_json, weeks_json,
+		total_overtime, comp_time_earned, other_paid_description, gross_earnings, current_balance, new_balance,
+		status, submitted_at, reviewed_at, reviewer_comment, reopened_at, reopen_reason, profile_json)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT(profile_id, period_start) DO UPDATE SET
+		period_end = excluded.period_end,
 		total_worked = excluded.total_worked,
-		entries_json = excluded.entries_json;
+		entries_json = excluded.entries_json,
+		weeks_json = excluded.weeks_json,
+		total_overtime = excluded.total_overtime,
+		comp_time_earned = excluded.comp_time_earned,
+		other_paid_description = excluded.other_paid_description,
+		gross_earnings = excluded.gross_earnings,
+		current_balance = excluded.current_balance,
+		new_balance = excluded.new_balance,
+		profile_json = COALESCE(excluded.profile_json, timesheets.profile_json)
+	WHERE timesheets.status NOT IN ('submitted', 'approved');
 	`
 	// Execute the query
-	_, err = r.Conn.Exec(query, t.Month, t.Year, t.TotalWorked, string(entriesData))
+	res, err := tx.Exec(query, t.ProfileID, t.Month, t.Year, t.PeriodStart, t.PeriodEnd, t.TotalWorked, string(entriesData), string(weeksData),
+		t.TotalOvertime, t.CompTimeEarned, t.OtherPaidDescription, t.GrossEarnings, t.CurrentBalance, t.NewBalance,
+		status, formatTime(t.SubmittedAt), formatTime(t.ReviewedAt), t.ReviewerComment, formatTime(t.ReopenedAt), t.ReopenReason,
+		profileData)
+	if err != nil {
+		return err
+	}
+
+	// Nothing written means the conflict update was skipped
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrTimesheetLocked
+	}
+
+	note := t.SaveNote
+	if note == "" {
+		note = "Saved"
+	}
+	err = recordRevision(tx, models.TimesheetRevision{
+		ProfileID:   t.ProfileID,
+		Month:       t.Month,
+		Year:        t.Year,
+		PeriodStart: t.PeriodStart,
+		Status:      status,
+		Note:        note,
+		Entries:     t.Entries,
+		Changes:     models.DiffEntries(previous, t.Entries),
+	})
+	if err != nil {
+		return err
+	}
+
+	// The edits are on the sheet now, drop any unsaved draft of it
+	if _, err := tx.Exec(`DELETE FROM timesheet_journal WHERE profile_id = ? AND period_start = ?`, t.ProfileID, t.PeriodStart); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// SetTimesheetStatus writes a sheet's approval status, timestamps and comments, and
+// records the change in the revision history
+func (r *Repository) SetTimesheetStatus(t *models.Timesheet) error {
+	tx, err := r.Conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	period := t.Period()
+	entries, _, err := savedEntries(tx, t.ProfileID, period.Key())
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`UPDATE timesheets SET status = ?, submitted_at = ?, reviewed_at = ?,
+		reviewer_comment = ?, reopened_at = ?, reopen_reason = ?
+		WHERE profile_id = ? AND period_start = ?`,
+		t.Status, formatTime(t.SubmittedAt), formatTime(t.ReviewedAt), t.ReviewerComment,
+		formatTime(t.ReopenedAt), t.ReopenReason, t.ProfileID, period.Key())
+	if err != nil {
+		return err
+	}
+
+	note := t.Status.Label()
+	switch t.Status {
+	case models.StatusDraft:
+		note = "Reopened: " + t.ReopenReason
+	case models.StatusApproved, models.StatusReturned:
+		if t.ReviewerComment != "" {
+			note += ": " + t.ReviewerComment
+		}
+	}
+	err = recordRevision(tx, models.TimesheetRevision{
+		ProfileID:   t.ProfileID,
+		Month:       int(period.Start.Month()),
+		Year:        period.Start.Year(),
+		PeriodStart: period.Key(),
+		Status:      t.Status,
+		Note:        note,
+		Entries:     entries,
+	})
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetTimesheetRevisions lists a pay period's revisions, newest first
+func (r *Repository) GetTimesheetRevisions(profileID int64, period models.Period) ([]models.TimesheetRevision, error) {
+	rows, err := r.Conn.Query(`SELECT id, profile_id, month, year, period_start, saved_at, status, note, entries_json, changes_json
+		FROM timesheet_revisions WHERE profile_id = ? AND period_start = ? ORDER BY id DESC`,
+		profileID, period.Key())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []models.TimesheetRevision
+	for rows.Next() {
+		var rev models.TimesheetRevision
+		var savedAt string
+		var status, note, entriesBlob, changesBlob sql.NullString
+		if err := rows.Scan(&rev.ID, &rev.ProfileID, &rev.Month, &rev.Year, &rev.PeriodStart, &savedAt, &status, &note, &entriesBlob, &changesBlob); err != nil {
+			return nil, err
+		}
+		rev.SavedAt = parseTime(savedAt)
+		rev.Status = models.TimesheetStatus(status.String)
+		rev.Note = note.String
+
+		if entriesBlob.String != "" {
+			if err := json.Unmarshal([]byte(entriesBlob.String), &rev.Entries); err != nil {
+				return nil, fmt.Errorf("revision %d: %w", rev.ID, err)
+			}
+		}
+		if changesBlob.String != "" {
+			if err := json.Unmarshal([]byte(changesBlob.String), &rev.Changes); err != nil {
+				return nil, fmt.Errorf("revision %d: %w", rev.ID, err)
+			}
+		}
+		revisions = append(revisions, rev)
+	}
+	return revisions, rows.Err()
+}
+
+// Helper to read a sheet's saved entries and status inside a transaction. Empty if not saved yet
+func savedEntries(tx *sql.Tx, profileID int64, periodStart string) (map[string]models.DailyEntry, models.TimesheetStatus, error) {
+	var blob, status sql.NullString
+	err := tx.QueryRow(`SELECT entries_json, status FROM timesheets WHERE profile_id = ? AND period_start = ?`,
+		profileID, periodStart).Scan(&blob, &status)
+	if err == sql.ErrNoRows {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	var entries map[string]models.DailyEntry
+	if blob.String != "" {
+		if err := json.Unmarshal([]byte(blob.String), &entries); err != nil {
+			return nil, "", err
+		}
+	}
+	return entries, models.TimesheetStatus(status.String), nil
+}
+
+// Helper to append a revision row
+func recordRevision(tx *sql.Tx, rev models.TimesheetRevision) error {
+	entriesData, err := json.Marshal(rev.Entries)
+	if err != nil {
+		return err
+	}
+	changesData, err := json.Marshal(rev.Changes)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT INTO timesheet_revisions (profile_id, month, year, period_start, saved_at, status, note, entries_json, changes_json)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		rev.ProfileID, rev.Month, rev.Year, rev.PeriodStart, formatTime(time.Now()), rev.Status, rev.Note, string(entriesData), string(changesData))
 	return err
 }
 
-func (r *Repository) GetTimesheets() ([]models.Timesheet, error) {
-	rows, err := r.Conn.Query(`SELECT id, month, year, total_worked, entries_json FROM timesheets ORDER BY year DESC, month DESC`)
+// GetTimesheets lists a profile's timesheets, newest first
+func (r *Repository) GetTimesheets(profileID int64) ([]models.Timesheet, error) {
+	rows, err := r.Conn.Query(`SELECT `+timesheetColumns+` FROM timesheets WHERE profile_id = ? ORDER BY period_start DESC`, profileID)
 	if err != nil {
 		return nil, err
 	}
@@ -120,32 +458,34 @@ func (r *Repository) GetTimesheets() ([]models.Timesheet, error) {
 
 	var sheets []models.Timesheet
 	for rows.Next() {
-		var t models.Timesheet
-		var jsonBlob string
-		if err := rows.Scan(&t.ID, &t.Month, &t.Year, &t.TotalWorked, &jsonBlob); err != nil {
+		t, err := scanTimesheet(rows)
+		if err != nil {
 			return nil, err
 		}
-
-		//Unmarshal entries JSON
-		json.Unmarshal([]byte(jsonBlob), &t.Entries)
-		sheets = append(sheets, t)
+		sheets = append(sheets, *t)
 	}
 
-	return sheets, nil
+	return sheets, rows.Err()
 }
 
-// Helper to extract timesheet by month and year
-func (r *Repository) GetTimesheetByDate(month int, year int) (*models.Timesheet, error) {
-	// Get timesheet from db
-	query := `SELECT id, month, year, total_worked, entries_json FROM timesheets WHERE month = ? AND year = ?`
-	row := r.Conn.QueryRow(query, month, year)
+// GetTimesheetForPeriod returns the profile's sheet that starts on the period's first day, or nil
+func (r *Repository) GetTimesheetForPeriod(profileID int64, period models.Period) (*models.Timesheet, error) {
+	query := `SELECT ` + timesheetColumns + ` FROM timesheets WHERE profile_id = ? AND period_start = ?`
+	return getTimesheet(r.Conn.QueryRow(query, profileID, period.Key()))
+}
 
-	// Timesheet and blob
-	var t models.Timesheet
-	var jsonBlob string
+// GetTimesheetOn returns the profile's saved sheet that covers a date, or nil
+func (r *Repository) GetTimesheetOn(profileID int64, date time.Time) (*models.Timesheet, error) {
+	day := date.Format("2006-01-02")
+	query := `SELECT ` + timesheetColumns + ` FROM timesheets WHERE profile_id = ? AND period_start <= ? AND period_end >= ?
+		ORDER BY period_start DESC LIMIT 1`
+	return getTimesheet(r.Conn.QueryRow(query, profileID, day, day))
+}
 
-	// error handling for query
-	if err := row.Scan(&t.ID, &t.Month, &t.Year, &t.TotalWorked, &jsonBlob); err != nil {
+// Helper to scan a single timesheet, nil when there is no row
+func getTimesheet(row *sql.Row) (*models.Timesheet, error) {
+	t, err := scanTimesheet(row)
+	if err != nil {
 		// no rows found
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -154,11 +494,609 @@ func (r *Repository) GetTimesheetByDate(month int, year int) (*models.Timesheet,
 		return nil, err
 	}
 
+	//Return timesheet, no error
+	return t, nil
+}
+
+// ResolvePeriod finds the pay period that holds a date, and its saved sheet if any.
+// A saved sheet keeps the period it was saved with, so monthly sheets still load
+// after the profile switches to pay periods. Otherwise the profile's definition
+// applies, trimmed so it never overlaps a saved sheet.
+func (r *Repository) ResolvePeriod(p *models.Profile, date time.Time) (models.Period, *models.Timesheet, error) {
+	saved, err := r.GetTimesheetOn(p.ID, date)
+	if err != nil {
+		return models.Period{}, nil, err
+	}
+	if saved != nil {
+		return saved.Period(), saved, nil
+	}
+
+	period := p.PayPeriod.PeriodFor(date)
+	day := date.Format("2006-01-02")
+	rows, err := r.Conn.Query(`SELECT period_start, period_end FROM timesheets
+		WHERE profile_id = ? AND period_start <= ? AND period_end >= ?`, p.ID, period.EndKey(), period.Key())
+	if err != nil {
+		return models.Period{}, nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var start, end string
+		if err := rows.Scan(&start, &end); err != nil {
+			return models.Period{}, nil, err
+		}
+		other, err := models.ParsePeriod(start, end)
+		if err != nil {
+			continue
+		}
+		if other.EndKey() < day && !other.End.Before(period.Start) {
+			period.Start = other.End.AddDate(0, 0, 1)
+		}
+		if other.Key() > day && !other.Start.After(period.End) {
+			period.End = other.Start.AddDate(0, 0, -1)
+		}
+	}
+	return period, nil, rows.Err()
+}
+
+// GetCarryInEntries returns the saved entries for the days before start that fall in
+// the same Mon-Sun workweek, plus the Sunday before that week whose night shift may
+// run into it. They come from whichever earlier sheets cover those days.
+func (r *Repository) GetCarryInEntries(profileID int64, start time.Time) (map[string]models.DailyEntry, error) {
+	carry := make(map[string]models.DailyEntry)
+
+	// Walk back from the day before the period to the Sunday before that week
+	from := start.AddDate(0, 0, -1)
+	for from.Weekday() != time.Sunday {
+		from = from.AddDate(0, 0, -1)
+	}
+	first, last := from.Format("2006-01-02"), start.AddDate(0, 0, -1).Format("2006-01-02")
+
+	rows, err := r.Conn.Query(`SELECT `+timesheetColumns+` FROM timesheets
+		WHERE profile_id = ? AND period_start <= ? AND period_end >= ?`, profileID, last, first)
+	if err != nil {
+		return carry, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		prev, err := scanTimesheet(rows)
+		if err != nil {
+			return carry, err
+		}
+		for dateStr, entry := range prev.Entries {
+			if dateStr >= first && dateStr <= last {
+				carry[dateStr] = entry
+			}
+		}
+	}
+
+	return carry, rows.Err()
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanTimesheet reads one timesheet row selected with timesheetColumns
+func scanTimesheet(row rowScanner) (*models.Timesheet, error) {
+	var t models.Timesheet
+	var entriesBlob string
+	var weeksBlob, otherPaidDesc sql.NullString
+	var totalOT, compEarned, gross, currentBal, newBal sql.NullFloat64
+	var status, submittedAt, reviewedAt, reviewerComment, reopenedAt, reopenReason, profileBlob sql.NullString
+
+	if err := row.Scan(&t.ID, &t.ProfileID, &t.Month, &t.Year, &t.PeriodStart, &t.PeriodEnd, &t.TotalWorked, &entriesBlob, &weeksBlob,
+		&totalOT, &compEarned, &otherPaidDesc, &gross, &currentBal, &newBal,
+		&status, &submittedAt, &reviewedAt, &reviewerComment, &reopenedAt, &reopenReason, &profileBlob); err != nil {
+		return nil, err
+	}
+
 	//Error handling for json
-	if err := json.Unmarshal([]byte(jsonBlob), &t.Entries); err != nil {
+	if err := json.Unmarshal([]byte(entriesBlob), &t.Entries); err != nil {
 		return nil, err
 	}
 
-	//Return timesheet, no error
+	// Sheets saved before v1 have no weekly rollups
+	if weeksBlob.Valid && weeksBlob.String != "" {
+		if err := json.Unmarshal([]byte(weeksBlob.String), &t.Weeks); err != nil {
+			return nil, err
+		}
+	}
+
+	t.TotalOvertime = totalOT.Float64
+	t.CompTimeEarned = compEarned.Float64
+	t.OtherPaidDescription = otherPaidDesc.String
+	t.GrossEarnings = gross.Float64
+	t.CurrentBalance = currentBal.Float64
+	t.NewBalance = newBal.Float64
+
+	t.Status = models.TimesheetStatus(status.String)
+	if t.Status == "" {
+		t.Status = models.StatusDraft
+	}
+	t.SubmittedAt = parseTime(submittedAt.String)
+	t.ReviewedAt = parseTime(reviewedAt.String)
+	t.ReviewerComment = reviewerComment.String
+	t.ReopenedAt = parseTime(reopenedAt.String)
+	t.ReopenReason = reopenReason.String
+
+	// Sheets saved before v7 have no snapshot
+	if profileBlob.String != "" {
+		var snap models.Profile
+		if err := json.Unmarshal([]byte(profileBlob.String), &snap); err != nil {
+			return nil, err
+		}
+		t.ProfileSnapshot = &snap
+	}
+
 	return &t, nil
 }
+
+// Helpers to store timestamps as RFC3339 text, empty for the zero time
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func parseTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+/* WORK-STUDY BALANCE */
+
+// ApplyWorkStudyBalance fills the balance and earnings fields of a work-study timesheet.
+// The starting balance is the NewBalance stored on the latest earlier sheet of the
+// semester; the first sheet of a semester starts from the profile's starting balance.
+func (r *Repository) ApplyWorkStudyBalance(p *models.Profile, t *models.Timesheet) error {
+	previous, err := r.workStudyBalanceBefore(p, t.Period().Start)
+	if err != nil {
+		return err
+	}
+
+	used := sumHoursWorked(t.Entries)
+	t.CurrentBalance = used
+	t.NewBalance = previous - used
+	t.GrossEarnings = rules.EstimateEarnings(p, t, rules.PolicyFor(p.Type)).Gross
+	return nil
+}
+
+// workStudyBalanceBefore finds the balance remaining on the day a period starts. A gap
+// in saved sheets keeps the balance, only a new semester resets it
+func (r *Repository) workStudyBalanceBefore(p *models.Profile, start time.Time) (float64, error) {
+	sheets, err := r.GetTimesheets(p.ID)
+	if err != nil {
+		return 0, fmt.Errorf("load balance before %s: %w", start.Format("Jan 2, 2006"), err)
+	}
+
+	// Newest first, so the first earlier work-study sheet holds the balance
+	for _, prev := range sheets {
+		period := prev.Period()
+		if !period.End.Before(start) {
+			continue
+		}
+		if !p.InSemester(period.Start) {
+			break
+		}
+		if prev.ReportProfile(p).Type == models.TypeWorkStudy {
+			return prev.NewBalance, nil
+		}
+	}
+
+	return p.StartingBalance(), nil
+}
+
+// Helper to total hours worked across daily entries
+func sumHoursWorked(entries map[string]models.DailyEntry) float64 {
+	var total float64
+	for _, e := range entries {
+		total += e.HoursWorked
+	}
+	return total
+}
+
+/* OVERTIME POLICIES */
+
+// Settings key prefix for per-employee-type overtime policies
+const overtimePolicyKey = "overtime_policy:"
+
+// LoadOvertimePolicies installs every saved policy into the rules engine
+func (r *Repository) LoadOvertimePolicies() error {
+	rows, err := r.Conn.Query(`SELECT key, value FROM settings WHERE key LIKE ?`, overtimePolicyKey+"%")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return err
+		}
+
+		// Start from the default so settings saved before a field existed keep its default
+		empType := models.EmployeeType(strings.TrimPrefix(key, overtimePolicyKey))
+		p := rules.DefaultPolicy(empType)
+		if err := json.Unmarshal([]byte(value), &p); err != nil {
+			return fmt.Errorf("overtime policy %s: %w", key, err)
+		}
+		rules.SetPolicy(empType, p)
+	}
+
+	return rows.Err()
+}
+
+// SaveOvertimePolicy stores a policy for an employee type and makes it active
+func (r *Repository) SaveOvertimePolicy(t models.EmployeeType, p rules.Policy) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.Conn.Exec(`INSERT OR REPLACE INTO settings (key, value) VALUES (?, ?)`, overtimePolicyKey+string(t), string(data))
+	if err != nil {
+		return err
+	}
+
+	rules.SetPolicy(t, p)
+	return nil
+}
+
+/* HOURS ROUNDING */
+
+// Settings key for the hours rounding rule
+const roundingKey = "hours_rounding"
+
+// LoadRounding installs the saved rounding rule, keeping the default when none is saved
+func (r *Repository) LoadRounding() error {
+	var value string
+	err := r.Conn.QueryRow(`SELECT value FROM settings WHERE key = ?`, roundingKey).Scan(&value)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var rule validation.Rounding
+	if err := json.Unmarshal([]byte(value), &rule); err != nil {
+		return fmt.Errorf("hours rounding: %w", err)
+	}
+	validation.SetRounding(rule)
+	return nil
+}
+
+// SaveRounding stores the rounding rule and makes it active
+func (r *Repository) SaveRounding(rule validation.Rounding) error {
+	data, err := json.Marshal(rule)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.Conn.Exec(`INSERT OR REPLACE INTO settings (key, value) VALUES (?, ?)`, roundingKey, string(data))
+	if err != nil {
+		return err
+	}
+
+	validation.SetRounding(rule)
+	return nil
+}
+
+/* AUTO-SAVE JOURNAL */
+
+// Settings key for the auto-save switch
+const autoSaveKey = "auto_save"
+
+// AutoSaveEnabled reports whether Calendar edits are journaled as they are typed. On unless turned off
+func (r *Repository) AutoSaveEnabled() (bool, error) {
+	var value string
+	err := r.Conn.QueryRow(`SELECT value FROM settings WHERE key = ?`, autoSaveKey).Scan(&value)
+	if err == sql.ErrNoRows {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return value != "off", nil
+}
+
+// SetAutoSaveEnabled turns the auto-save journal on or off
+func (r *Repository) SetAutoSaveEnabled(on bool) error {
+	value := "on"
+	if !on {
+		value = "off"
+	}
+	_, err := r.Conn.Exec(`INSERT OR REPLACE INTO settings (key, value) VALUES (?, ?)`, autoSaveKey, value)
+	return err
+}
+
+// WriteJournal stores the unsaved state of a sheet, replacing the previous draft
+func (r *Repository) WriteJournal(j models.JournalEntry) error {
+	entriesData, err := json.Marshal(j.Entries)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.Conn.Exec(`INSERT OR REPLACE INTO timesheet_journal
+		(profile_id, period_start, period_end, saved_at, entries_json, other_paid_description)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		j.ProfileID, j.PeriodStart, j.PeriodEnd, formatTime(j.SavedAt), string(entriesData), j.OtherPaidDescription)
+	return err
+}
+
+// GetJournal returns the unsaved draft of a period's sheet, or nil
+func (r *Repository) GetJournal(profileID int64, period models.Period) (*models.JournalEntry, error) {
+	var j models.JournalEntry
+	var savedAt string
+	var entriesBlob, otherPaidDesc sql.NullString
+	err := r.Conn.QueryRow(`SELECT profile_id, period_start, period_end, saved_at, entries_json, other_paid_description
+		FROM timesheet_journal WHERE profile_id = ? AND period_start = ?`, profileID, period.Key()).
+		Scan(&j.ProfileID, &j.PeriodStart, &j.PeriodEnd, &savedAt, &entriesBlob, &otherPaidDesc)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	j.SavedAt = parseTime(savedAt)
+	j.OtherPaidDescription = otherPaidDesc.String
+	if entriesBlob.String != "" {
+		if err := json.Unmarshal([]byte(entriesBlob.String), &j.Entries); err != nil {
+			return nil, fmt.Errorf("journal %s: %w", j.PeriodStart, err)
+		}
+	}
+	return &j, nil
+}
+
+// ClearJournal drops the unsaved draft of a period's sheet
+func (r *Repository) ClearJournal(profileID int64, period models.Period) error {
+	_, err := r.Conn.Exec(`DELETE FROM timesheet_journal WHERE profile_id = ? AND period_start = ?`, profileID, period.Key())
+	return err
+}
+
+/* HOLIDAY METHODS */
+
+// GetHolidays returns every holiday and closure in date order
+func (r *Repository) GetHolidays() ([]models.Holiday, error) {
+	return r.queryHolidays(`SELECT date, name FROM holidays ORDER BY date`)
+}
+
+// GetHolidaysInPeriod returns the period's holidays keyed by date
+func (r *Repository) GetHolidaysInPeriod(period models.Period) (map[string]models.Holiday, error) {
+	list, err := r.queryHolidays(`SELECT date, name FROM holidays WHERE date BETWEEN ? AND ? ORDER BY date`,
+		period.Key(), period.EndKey())
+	if err != nil {
+		return nil, err
+	}
+
+	holidays := make(map[string]models.Holiday, len(list))
+	for _, h := range list {
+		holidays[h.Date] = h
+	}
+	return holidays, nil
+}
+
+// SaveHolidays adds or renames holidays in one transaction
+func (r *Repository) SaveHolidays(holidays []models.Holiday) error {
+	tx, err := r.Conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, h := range holidays {
+		if _, err := tx.Exec(`INSERT OR REPLACE INTO holidays (date, name) VALUES (?, ?)`, h.Date, h.Name); err != nil {
+			return fmt.Errorf("holiday %s: %w", h.Date, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// DeleteHoliday removes the holiday on a date
+func (r *Repository) DeleteHoliday(date string) error {
+	_, err := r.Conn.Exec(`DELETE FROM holidays WHERE date = ?`, date)
+	return err
+}
+
+// Helper to run a holiday query
+func (r *Repository) queryHolidays(query string, args ...any) ([]models.Holiday, error) {
+	rows, err := r.Conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var holidays []models.Holiday
+	for rows.Next() {
+		var h models.Holiday
+		if err := rows.Scan(&h.Date, &h.Name); err != nil {
+			return nil, err
+		}
+		holidays = append(holidays, h)
+	}
+	return holidays, rows.Err()
+}
+
+/* LEAVE LEDGER METHODS */
+
+// GetLeaveAccounts returns a profile's leave accounts keyed by leave type
+func (r *Repository) GetLeaveAccounts(profileID int64) (map[models.LeaveType]models.LeaveAccount, error) {
+	rows, err := r.Conn.Query(`SELECT profile_id, leave_type, starting_balance, accrual_rate, start_month, start_year
+		FROM leave_accounts WHERE profile_id = ?`, profileID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accounts := make(map[models.LeaveType]models.LeaveAccount)
+	for rows.Next() {
+		var a models.LeaveAccount
+		if err := rows.Scan(&a.ProfileID, &a.Type, &a.StartingBalance, &a.AccrualRate, &a.StartMonth, &a.StartYear); err != nil {
+			return nil, err
+		}
+		accounts[a.Type] = a
+	}
+	return accounts, rows.Err()
+}
+
+// SaveLeaveAccount creates or replaces a leave account
+func (r *Repository) SaveLeaveAccount(a models.LeaveAccount) error {
+	_, err := r.Conn.Exec(`INSERT OR REPLACE INTO leave_accounts
+		(profile_id, leave_type, starting_balance, accrual_rate, start_month, start_year)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		a.ProfileID, a.Type, a.StartingBalance, a.AccrualRate, a.StartMonth, a.StartYear)
+	return err
+}
+
+// GetLeaveLedger builds the month-by-month ledger for one leave account, from the
+// account's start through the given month. Returns nil if there is no account.
+func (r *Repository) GetLeaveLedger(profileID int64, t models.LeaveType, month int, year int) ([]models.LeaveLedgerLine, error) {
+	accounts, err := r.GetLeaveAccounts(profileID)
+	if err != nil {
+		return nil, err
+	}
+	account, ok := accounts[t]
+	if !ok {
+		return nil, nil
+	}
+
+	sheets, err := r.GetTimesheets(profileID)
+	if err != nil {
+		return nil, err
+	}
+	// Usage counts in the month it was taken, comp earned in the month its week ends
+	used := make(map[[2]int]float64)
+	earned := make(map[[2]int]float64)
+	for _, s := range sheets {
+		for date, e := range s.Entries {
+			if d, err := time.Parse("2006-01-02", date); err == nil {
+				used[[2]int{d.Year(), int(d.Month())}] += e.LeaveUsed(t)
+			}
+		}
+		for date, comp := range compEarnedByDate(s) {
+			if d, err := time.Parse("2006-01-02", date); err == nil {
+				earned[[2]int{d.Year(), int(d.Month())}] += comp
+			}
+		}
+	}
+
+	var ledger []models.LeaveLedgerLine
+	balance := account.StartingBalance
+	cur := time.Date(account.StartYear, time.Month(account.StartMonth), 1, 0, 0, 0, 0, time.Local)
+	end := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.Local)
+	for !cur.After(end) {
+		key := [2]int{cur.Year(), int(cur.Month())}
+		line := models.LeaveLedgerLine{
+			Month:   int(cur.Month()),
+			Year:    cur.Year(),
+			Opening: balance,
+			Accrued: account.AccrualRate,
+			Used:    used[key],
+		}
+		if t == models.LeaveComp {
+			line.Earned = earned[key]
+		}
+
+		balance += line.Accrued + line.Earned - line.Used
+		line.Balance = balance
+		ledger = append(ledger, line)
+		cur = cur.AddDate(0, 1, 0)
+	}
+
+	return ledger, nil
+}
+
+// compEarnedByDate splits a sheet's comp time earned across its workweeks by their
+// overtime, dated on each week's last day in the period. A pay period that crosses a
+// month end so credits each month with the weeks that ended in it. Sheets without
+// weekly overtime are dated on their first day
+func compEarnedByDate(s models.Timesheet) map[string]float64 {
+	earned := make(map[string]float64)
+	if s.CompTimeEarned == 0 {
+		return earned
+	}
+
+	period := s.Period()
+	if s.TotalOvertime <= 0 || len(s.Weeks) == 0 {
+		earned[period.Key()] = s.CompTimeEarned
+		return earned
+	}
+	for _, w := range s.Weeks {
+		if w.OvertimeTotal <= 0 {
+			continue
+		}
+		last := w.WeekEndDate
+		if last > period.EndKey() {
+			last = period.EndKey()
+		}
+		earned[last] += s.CompTimeEarned * w.OvertimeTotal / s.TotalOvertime
+	}
+	return earned
+}
+
+// GetLeavePeriod returns each leave account's position at the start of a pay period:
+// Opening is the balance after the start month's accrual and any leave taken on
+// earlier sheets that month (plus comp earned on them), Accrued is what later months
+// in the period add.
+// Accounts that start after the period's first month are left out.
+func (r *Repository) GetLeavePeriod(profileID int64, period models.Period) (map[models.LeaveType]models.LeaveLedgerLine, error) {
+	accounts, err := r.GetLeaveAccounts(profileID)
+	if err != nil {
+		return nil, err
+	}
+	sheets, err := r.GetTimesheets(profileID)
+	if err != nil {
+		return nil, err
+	}
+
+	monthStart := time.Date(period.Start.Year(), period.Start.Month(), 1, 0, 0, 0, 0, time.Local).Format("2006-01-02")
+	lines := make(map[models.LeaveType]models.LeaveLedgerLine)
+	for t, account := range accounts {
+		ledger, err := r.GetLeaveLedger(profileID, t, int(period.Start.Month()), period.Start.Year())
+		if err != nil {
+			return nil, err
+		}
+		if len(ledger) == 0 {
+			continue
+		}
+
+		line := ledger[len(ledger)-1]
+		line.Opening += line.Accrued
+		line.Accrued = 0
+		for _, s := range sheets {
+			if s.Period().Key() == period.Key() {
+				continue // The sheet on screen, counted live
+			}
+			for date, e := range s.Entries {
+				if date >= monthStart && date < period.Key() {
+					line.Opening -= e.LeaveUsed(t)
+				}
+			}
+			if t == models.LeaveComp {
+				for date, comp := range compEarnedByDate(s) {
+					if date >= monthStart && date < period.Key() {
+						line.Opening += comp
+					}
+				}
+			}
+		}
+		for m := period.Start.AddDate(0, 1, 0); ; m = m.AddDate(0, 1, 0) {
+			first := time.Date(m.Year(), m.Month(), 1, 0, 0, 0, 0, time.Local)
+			if first.After(period.End) {
+				break
+			}
+			line.Accrued += account.AccrualRate
+		}
+		line.Used, line.Earned, line.Balance = 0, 0, 0
+		lines[t] = line
+	}
+	return lines, nil
+}
@@ -0,0 +1,76 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"calendar_utility_node_for_timesheets/models"
+)
+
+// Helper saving a month of a work-study profile with its hours on the 2nd, balance applied
+func saveWorkStudyMonth(t *testing.T, repo *Repository, p *models.Profile, year int, month time.Month, hours float64) models.Timesheet {
+	t.Helper()
+	period := models.MonthPeriod(year, month)
+	date := period.Start.AddDate(0, 0, 1).Format("2006-01-02")
+
+	ts := models.Timesheet{
+		ProfileID: p.ID,
+		Entries:   map[string]models.DailyEntry{date: {Date: date, HoursWorked: hours}},
+	}
+	ts.SetPeriod(period)
+	if err := repo.ApplyWorkStudyBalance(p, &ts); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.SaveTimesheet(ts); err != nil {
+		t.Fatal(err)
+	}
+	return ts
+}
+
+func TestWorkStudyBalance(t *testing.T) {
+	zero := 0.0
+	tests := []struct {
+		name     string
+		profile  models.Profile
+		months   []time.Month // Saved in order, one 10 hour sheet each
+		override float64      // Stored NewBalance written over the first sheet, 0 to keep it
+		want     float64      // NewBalance of the last sheet
+	}{
+		{"full allocation", models.Profile{SemesterAllocation: 100}, []time.Month{time.February}, 0, 90},
+		{"carries forward", models.Profile{SemesterAllocation: 100}, []time.Month{time.February, time.March}, 0, 80},
+		{"gap keeps balance", models.Profile{SemesterAllocation: 100}, []time.Month{time.February, time.April}, 0, 80},
+		{"stored balance wins", models.Profile{SemesterAllocation: 100}, []time.Month{time.February, time.March}, 50, 40},
+		{"zero starting balance", models.Profile{SemesterAllocation: 100, PreviousBalance: &zero}, []time.Month{time.February}, 0, -10},
+		{"new semester resets", models.Profile{SemesterAllocation: 100, SemesterStart: "2026-03-01"}, []time.Month{time.February, time.March}, 0, 90},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := NewRepository(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer repo.Conn.Close()
+
+			p := tt.profile
+			p.FirstName, p.Type = "Ada", models.TypeWorkStudy
+			if err := repo.SaveProfile(&p); err != nil {
+				t.Fatal(err)
+			}
+
+			var last models.Timesheet
+			for i, month := range tt.months {
+				last = saveWorkStudyMonth(t, repo, &p, 2026, month, 10)
+				if i == 0 && tt.override != 0 {
+					last.NewBalance = tt.override
+					if err := repo.SaveTimesheet(last); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+			if last.NewBalance != tt.want {
+				t.Errorf("new balance = %v, want %v", last.NewBalance, tt.want)
+			}
+		})
+	}
+}
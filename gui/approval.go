@@ -0,0 +1,200 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"calendar_utility_node_for_timesheets/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Approval workflow controls for the Calendar tab. A submitted or approved sheet is
+// locked until it is reopened with a reason.
+
+// Create the status label and workflow buttons
+func (c *CalendarPage) initApproval() {
+	c.StatusLabel = widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	c.ReviewNoteLabel = widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Italic: true})
+	c.ReviewNoteLabel.Truncation = fyne.TextTruncateEllipsis
+
+	c.SnapshotLabel = widget.NewLabel("")
+	c.SnapshotLabel.Importance = widget.WarningImportance
+	c.SnapshotLabel.Wrapping = fyne.TextWrapWord
+	c.SnapshotLabel.Hide()
+
+	c.SubmitBtn = widget.NewButtonWithIcon("Submit", theme.MailSendIcon(), c.submitSheet)
+	c.ReviewBtn = widget.NewButtonWithIcon("Review", theme.ConfirmIcon(), c.reviewSheet)
+	c.ReopenBtn = widget.NewButtonWithIcon("Reopen", theme.ViewRefreshIcon(), c.reopenSheet)
+}
+
+// Show the sheet's status and lock the inputs to match it
+func (c *CalendarPage) syncApproval(ts *models.Timesheet) {
+	c.Status = models.StatusDraft
+	c.StatusLabel.SetText("")
+	c.ReviewNoteLabel.SetText("")
+	c.SnapshotLabel.Hide()
+	c.SubmitBtn.Hide()
+	c.ReviewBtn.Hide()
+	c.ReopenBtn.Hide()
+
+	if c.Profile == nil {
+		return
+	}
+
+	// Unsaved periods are drafts
+	status := fmt.Sprintf("Status: %s", models.StatusDraft.Label())
+	if ts != nil {
+		c.Status = ts.Status
+		status = fmt.Sprintf("Status: %s", ts.Status.Label())
+		switch ts.Status {
+		case models.StatusSubmitted:
+			status += " " + ts.SubmittedAt.Format("Jan 2 15:04")
+		case models.StatusApproved, models.StatusReturned:
+			status += " " + ts.ReviewedAt.Format("Jan 2 15:04")
+		case models.StatusDraft:
+			if ts.ReopenReason != "" {
+				c.ReviewNoteLabel.SetText("Reopened: " + ts.ReopenReason)
+			}
+		}
+		if ts.ReviewerComment != "" && ts.Status != models.StatusDraft {
+			c.ReviewNoteLabel.SetText("Reviewer: " + ts.ReviewerComment)
+		}
+	}
+	c.StatusLabel.SetText(status)
+	c.syncSnapshotNote(ts)
+
+	switch c.Status {
+	case models.StatusSubmitted:
+		c.ReviewBtn.Show()
+		c.ReopenBtn.Show()
+	case models.StatusApproved:
+		c.ReopenBtn.Show()
+	default:
+		c.SubmitBtn.Show()
+	}
+
+	locked := c.locked()
+	for _, cell := range c.DayWidgets {
+		cell.SetLocked(locked)
+	}
+	if locked {
+		c.OtherPaidDescEntry.Disable()
+		c.SaveBtn.Disable()
+		c.BulkBtn.Disable()
+	} else {
+		c.OtherPaidDescEntry.Enable()
+		c.SaveBtn.Enable()
+		c.BulkBtn.Enable()
+	}
+}
+
+// Flag a sheet whose saved profile snapshot no longer matches the live profile
+func (c *CalendarPage) syncSnapshotNote(ts *models.Timesheet) {
+	if ts == nil {
+		return
+	}
+	changes := models.ProfileChanges(ts.ProfileSnapshot, c.Profile)
+	if len(changes) == 0 {
+		return
+	}
+
+	note := "Profile changed since this sheet was saved (" + strings.Join(changes, ", ") + ")."
+	if c.locked() {
+		note += " Reports keep the saved details."
+	} else {
+		note += " Saving will use the current profile."
+	}
+	c.SnapshotLabel.SetText(note)
+	c.SnapshotLabel.Show()
+}
+
+// Whether the period on screen is submitted or approved
+func (c *CalendarPage) locked() bool {
+	return c.Status == models.StatusSubmitted || c.Status == models.StatusApproved
+}
+
+// Save the latest edits and send the sheet for review
+func (c *CalendarPage) submitSheet() {
+	if c.Profile == nil {
+		return
+	}
+
+	msg := fmt.Sprintf("Submit the %s timesheet? Hours will be locked until it is reopened.", c.Period.Label())
+	dialog.ShowConfirm("Submit Timesheet", msg, func(ok bool) {
+		if !ok {
+			return
+		}
+		if err := c.storeTimesheet(); err != nil {
+			dialog.ShowError(err, c.Window)
+			return
+		}
+		c.changeStatus(func(ts *models.Timesheet) error {
+			return ts.Submit(time.Now())
+		})
+	}, c.Window)
+}
+
+// Approve or return a submitted sheet with a comment
+func (c *CalendarPage) reviewSheet() {
+	decision := widget.NewRadioGroup([]string{"Approve", "Return"}, nil)
+	decision.SetSelected("Approve")
+	comment := widget.NewMultiLineEntry()
+	comment.SetPlaceHolder("Required when returning")
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Decision", decision),
+		widget.NewFormItem("Comment", comment),
+	}
+	dialog.ShowForm("Review Timesheet", "Save", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		c.changeStatus(func(ts *models.Timesheet) error {
+			return ts.Review(decision.Selected == "Approve", comment.Text, time.Now())
+		})
+	}, c.Window)
+}
+
+// Unlock a submitted or approved sheet, recording why
+func (c *CalendarPage) reopenSheet() {
+	reason := widget.NewMultiLineEntry()
+	reason.SetPlaceHolder("Why does this sheet need changes?")
+
+	items := []*widget.FormItem{widget.NewFormItem("Reason", reason)}
+	dialog.ShowForm("Reopen Timesheet", "Reopen", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		c.changeStatus(func(ts *models.Timesheet) error {
+			return ts.Reopen(reason.Text, time.Now())
+		})
+	}, c.Window)
+}
+
+// Helper to apply a workflow step to the saved sheet and redraw
+func (c *CalendarPage) changeStatus(apply func(ts *models.Timesheet) error) {
+	ts, err := c.Repo.GetTimesheetForPeriod(c.Profile.ID, c.Period)
+	if err != nil {
+		dialog.ShowError(err, c.Window)
+		return
+	}
+	if ts == nil {
+		dialog.ShowInformation("Not Saved", "Save the timesheet first", c.Window)
+		return
+	}
+
+	if err := apply(ts); err != nil {
+		dialog.ShowError(err, c.Window)
+		return
+	}
+	if err := c.Repo.SetTimesheetStatus(ts); err != nil {
+		dialog.ShowError(err, c.Window)
+		return
+	}
+	c.Refresh()
+}
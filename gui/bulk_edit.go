@@ -0,0 +1,359 @@
+package gui
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"calendar_utility_node_for_timesheets/models"
+	"calendar_utility_node_for_timesheets/rules"
+	"calendar_utility_node_for_timesheets/validation"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Bulk edits on the Calendar tab. Each tool builds the new days, checks them the same way
+// as typed hours and puts them in the cells as one undoable step. Nothing is written to
+// the DB until Save Changes.
+
+// Columns a date range can be filled with. Only full-time sheets have the leave columns
+var fillColumns = []string{"Hours Worked", "Sick Leave", "Vacation", "Holiday", "Comp Time", "Other Paid"}
+
+// Create the Bulk Edit menu button
+func (c *CalendarPage) initBulkEdit() {
+	c.BulkBtn = widget.NewButtonWithIcon("Bulk Edit", theme.ContentPasteIcon(), nil)
+	c.BulkBtn.OnTapped = func() {
+		menu := fyne.NewMenu("",
+			fyne.NewMenuItem("Copy Week...", c.showCopyWeek),
+			fyne.NewMenuItem("Fill Range...", c.showFillRange),
+			fyne.NewMenuItem("Re-apply Schedule...", c.showReapplySchedule),
+			fyne.NewMenuItemSeparator(),
+			fyne.NewMenuItem("Clear All Days...", c.confirmClearDays),
+		)
+		pos := fyne.NewPos(0, c.BulkBtn.Size().Height)
+		widget.ShowPopUpMenuAtRelativePosition(menu, c.Window.Canvas(), pos, c.BulkBtn)
+	}
+}
+
+// Whether a bulk edit may start, explaining why not
+func (c *CalendarPage) canBulkEdit() bool {
+	if c.Profile == nil {
+		dialog.ShowInformation("No Profile", "Create a profile before editing timesheets", c.Window)
+		return false
+	}
+	if c.locked() {
+		dialog.ShowInformation("Timesheet Locked", "This timesheet is "+string(c.Status)+". Reopen it to make changes.", c.Window)
+		return false
+	}
+	return true
+}
+
+// Copy the hours of one week onto other weeks, weekday by weekday
+func (c *CalendarPage) showCopyWeek() {
+	if !c.canBulkEdit() {
+		return
+	}
+
+	starts := rules.PeriodWeekStarts(c.Period)
+	if len(starts) < 2 {
+		dialog.ShowInformation("Copy Week", "This period has only one week.", c.Window)
+		return
+	}
+	labels := make([]string, len(starts))
+	for i, start := range starts {
+		labels[i] = "Week of " + start.Format("Mon Jan 2")
+	}
+
+	fromSelect := widget.NewSelect(labels, nil)
+	toGroup := widget.NewCheckGroup(labels, nil)
+	fromSelect.OnChanged = func(string) {
+		// A week is not copied onto itself
+		var keep []string
+		for _, label := range toGroup.Selected {
+			if label != fromSelect.Selected {
+				keep = append(keep, label)
+			}
+		}
+		toGroup.SetSelected(keep)
+	}
+	fromSelect.SetSelectedIndex(0)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Copy", fromSelect),
+		widget.NewFormItem("Onto", toGroup),
+	}
+	dialog.ShowForm("Copy Week", "Copy", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		from := starts[fromSelect.SelectedIndex()]
+		entries := make(map[string]models.DailyEntry)
+		for _, label := range toGroup.Selected {
+			to := starts[indexOf(labels, label)]
+			if to.Equal(from) {
+				continue
+			}
+			for day := 0; day < 7; day++ {
+				src, ok := c.DayWidgets[from.AddDate(0, 0, day).Format("2006-01-02")]
+				if !ok {
+					continue
+				}
+				date := to.AddDate(0, 0, day).Format("2006-01-02")
+				cell, ok := c.DayWidgets[date]
+				if !ok || cell.closed {
+					continue
+				}
+
+				// Hours only, clock punches belong to the day they were taken
+				e := src.GetData()
+				e.Date = date
+				e.Closure = cell.Closure
+				e.Punches = nil
+				entries[date] = e
+			}
+		}
+		if len(entries) == 0 {
+			dialog.ShowInformation("Copy Week", "Choose at least one week to copy onto.", c.Window)
+			return
+		}
+		c.applyBulk("copy "+strings.ToLower(fromSelect.Selected), entries)
+	}, c.Window)
+}
+
+// Set one column to the same hours on every day of a date range
+func (c *CalendarPage) showFillRange() {
+	if !c.canBulkEdit() {
+		return
+	}
+
+	columns := fillColumns[:1]
+	if c.Profile.Type == models.TypeFullTime {
+		columns = fillColumns
+	}
+
+	fromEntry, toEntry := c.rangeEntries()
+	columnSelect := widget.NewSelect(columns, nil)
+	columnSelect.SetSelectedIndex(0)
+	hoursEntry := widget.NewEntry()
+	hoursEntry.SetPlaceHolder("8")
+	hoursEntry.Validator = validation.CheckHours
+	weekdaysCheck := widget.NewCheck("Weekdays only", nil)
+	weekdaysCheck.SetChecked(true)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("From", fromEntry),
+		widget.NewFormItem("To", toEntry),
+		widget.NewFormItem("Column", columnSelect),
+		widget.NewFormItem("Hours", hoursEntry),
+		widget.NewFormItem("", weekdaysCheck),
+	}
+	dialog.ShowForm("Fill Range", "Fill", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		dates, err := c.parseRange(fromEntry.Text, toEntry.Text, weekdaysCheck.Checked)
+		if err != nil {
+			dialog.ShowError(err, c.Window)
+			return
+		}
+		hours, err := validation.ParseHours(hoursEntry.Text)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("hours: %v", err), c.Window)
+			return
+		}
+
+		entries := make(map[string]models.DailyEntry)
+		for _, date := range dates {
+			cell := c.DayWidgets[date]
+			if cell.closed {
+				continue
+			}
+			e := cell.GetData()
+			setColumn(&e, columnSelect.Selected, hours)
+			entries[date] = e
+		}
+		label := fmt.Sprintf("fill %s with %s", strings.ToLower(columnSelect.Selected), formatEntryHours(hours))
+		c.applyBulk(label, entries)
+	}, c.Window)
+}
+
+// Put the profile's schedule back on the chosen days, as on a sheet never saved
+func (c *CalendarPage) showReapplySchedule() {
+	if !c.canBulkEdit() {
+		return
+	}
+
+	fromEntry, toEntry := c.rangeEntries()
+	daysGroup := widget.NewCheckGroup(scheduleDays, nil)
+	daysGroup.Horizontal = true
+	daysGroup.SetSelected(scheduleDays)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("From", fromEntry),
+		widget.NewFormItem("To", toEntry),
+		widget.NewFormItem("Days", daysGroup),
+	}
+	dialog.ShowForm("Re-apply Schedule", "Apply", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		dates, err := c.parseRange(fromEntry.Text, toEntry.Text, false)
+		if err != nil {
+			dialog.ShowError(err, c.Window)
+			return
+		}
+
+		entries := make(map[string]models.DailyEntry)
+		for _, date := range dates {
+			day, _ := time.ParseInLocation("2006-01-02", date, time.Local)
+			if indexOf(daysGroup.Selected, scheduleDays[weekdayIndex(day)]) < 0 {
+				continue
+			}
+
+			sched := c.scheduleFor(day)
+			e := models.DailyEntry{
+				Date:           date,
+				HoursWorked:    sched.TotalHours(),
+				OvernightHours: sched.OvernightHours(),
+			}
+			if h, ok := c.Holidays[date]; ok {
+				e = c.applyHoliday(e, h, day, true)
+			}
+			entries[date] = e
+		}
+		if len(entries) == 0 {
+			dialog.ShowInformation("Re-apply Schedule", "No days in the range match the chosen weekdays.", c.Window)
+			return
+		}
+		c.applyBulk("re-apply schedule", entries)
+	}, c.Window)
+}
+
+// Empty every day of the period, keeping holiday and closure names
+func (c *CalendarPage) confirmClearDays() {
+	if !c.canBulkEdit() {
+		return
+	}
+
+	msg := fmt.Sprintf("Clear every day of %s? Undo puts them back, nothing is saved until Save Changes.", c.Period.Label())
+	dialog.ShowConfirm("Clear All Days", msg, func(ok bool) {
+		if !ok {
+			return
+		}
+		entries := make(map[string]models.DailyEntry)
+		for date, cell := range c.DayWidgets {
+			entries[date] = models.DailyEntry{Date: date, Closure: cell.Closure}
+		}
+		c.applyBulk("clear all days", entries)
+	}, c.Window)
+}
+
+// Check the new days like typed hours, then fill the cells as one step
+func (c *CalendarPage) applyBulk(label string, entries map[string]models.DailyEntry) {
+	dates := make([]string, 0, len(entries))
+	for date := range entries {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	for _, date := range dates {
+		if err := validation.ValidateDay(entries[date]); err != nil {
+			day, _ := time.Parse("2006-01-02", date)
+			dialog.ShowError(fmt.Errorf("%s: %v. Nothing was changed", day.Format("Mon Jan 2"), err), c.Window)
+			return
+		}
+	}
+
+	if err := c.fillCells(label, entries); err != nil {
+		dialog.ShowError(err, c.Window)
+	}
+}
+
+// Helper creating From/To date inputs preset to the period on screen
+func (c *CalendarPage) rangeEntries() (*widget.Entry, *widget.Entry) {
+	from := widget.NewEntry()
+	from.SetPlaceHolder("YYYY-MM-DD")
+	from.SetText(c.Period.Key())
+	to := widget.NewEntry()
+	to.SetPlaceHolder("YYYY-MM-DD")
+	to.SetText(c.Period.EndKey())
+	return from, to
+}
+
+// Helper reading a date range and listing its days on screen in order
+func (c *CalendarPage) parseRange(fromText, toText string, weekdaysOnly bool) ([]string, error) {
+	from, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(fromText), time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("from date %q is not YYYY-MM-DD", fromText)
+	}
+	to, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(toText), time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("to date %q is not YYYY-MM-DD", toText)
+	}
+	if to.Before(from) {
+		return nil, errors.New("the range ends before it starts")
+	}
+
+	var dates []string
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if weekdaysOnly && (day.Weekday() == time.Saturday || day.Weekday() == time.Sunday) {
+			continue
+		}
+		date := day.Format("2006-01-02")
+		if _, ok := c.DayWidgets[date]; ok {
+			dates = append(dates, date)
+		}
+	}
+	if len(dates) == 0 {
+		return nil, fmt.Errorf("no days of %s are in that range", c.Period.Label())
+	}
+	return dates, nil
+}
+
+// Helper setting the hours of one fill column
+func setColumn(e *models.DailyEntry, column string, hours float64) {
+	switch column {
+	case "Hours Worked":
+		e.HoursWorked = hours
+		e.SecondaryHours = 0
+		e.OvernightHours = 0
+		e.Punches = nil
+	case "Sick Leave":
+		e.SickLeave = hours
+	case "Vacation":
+		e.Vacation = hours
+	case "Holiday":
+		e.Holiday = hours
+	case "Comp Time":
+		e.CompTimeTaken = hours
+	case "Other Paid":
+		e.OtherPaid = hours
+	}
+}
+
+// Monday-first index of a weekday, as used by Profile.Schedule
+func weekdayIndex(day time.Time) int {
+	idx := int(day.Weekday()) - 1
+	if idx < 0 {
+		idx = 6
+	}
+	return idx
+}
+
+// Helper finding a string in a list, -1 when missing
+func indexOf(list []string, s string) int {
+	for i, item := range list {
+		if item == s {
+			return i
+		}
+	}
+	return -1
+}
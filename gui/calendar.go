@@ -4,27 +4,33 @@ import (
 	"fmt"
 	"image/color"
 	"log"
+	"os"
+	"sort"
+	"strings"
 	"time"
 
 	"calendar_utility_node_for_timesheets/db"
 	"calendar_utility_node_for_timesheets/models"
 	"calendar_utility_node_for_timesheets/pdfgen"
+	"calendar_utility_node_for_timesheets/rules"
+	"calendar_utility_node_for_timesheets/validation"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
-	"fyne.io/fyne/v2/storage"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 )
 
 type CalendarPage struct {
-	Repo   *db.Repository
-	Window fyne.Window
+	Repo    *db.Repository
+	Window  fyne.Window
+	History *UndoHistory // Shared with the Profile tab
 
 	// State
 	CurrentDate time.Time
+	Period      models.Period // Pay period holding CurrentDate, resolved on Refresh
 	Profile     *models.Profile
 	ShowDetails bool
 
@@ -35,19 +41,57 @@ type CalendarPage struct {
 	MonthlyRegularLabel  *widget.Label
 	MonthlyOvertimeLabel *widget.Label
 	MonthlyTotalLabel    *widget.Label
+	MonthlyEarningsLabel *widget.Label // Estimated gross pay
+	ValidationLabel      *widget.Label // Invalid hours that block saving
 	ToggleBtn            *widget.Button
+	OtherPaidDescEntry   *widget.Entry
+	ClockBtn             *widget.Button
+	TimerLabel           *widget.Label
+	LeaveWarningLabel    *widget.Label
+	SaveBtn              *widget.Button
+
+	// Approval workflow
+	Status          models.TimesheetStatus
+	StatusLabel     *widget.Label
+	ReviewNoteLabel *widget.Label
+	SnapshotLabel   *widget.Label
+	SubmitBtn       *widget.Button
+	ReviewBtn       *widget.Button
+	ReopenBtn       *widget.Button
 
 	// Data Management
 	DayWidgets            map[string]*DayCell
 	WeeklyStatsContainers []fyne.CanvasObject
+	CarryIn               map[string]models.DailyEntry                // Earlier sheets' days in the first workweek
+	Holidays              map[string]models.Holiday                   // This period's holidays and closures
+	LeavePeriod           map[models.LeaveType]models.LeaveLedgerLine // Full-time leave position at the period's start
+	carryInErr            error                                       // Set when CarryIn failed to load, saving would miscount overtime
+
+	// Unsaved changes and the auto-save journal
+	DirtyLabel     *widget.Label
+	AutoSaveCheck  *widget.Check
+	AutoSave       *db.AutoSaver
+	savedOtherDesc string    // Other paid explanation as last loaded or saved
+	recoveredAt    time.Time // When the draft put back in the cells was journaled
+	loading        bool      // Set while cells are filled, edits are not tracked
+
+	// Undo/redo of cell edits
+	UndoBtn   *widget.Button
+	RedoBtn   *widget.Button
+	BulkBtn   *widget.Button // Menu of bulk edits, see bulk_edit.go
+	undoScope string         // Sheet the cells belong to, see sheetScope
+	replaying bool           // Set while the history fills cells, edits are not recorded
+
+	clockStop chan struct{} // Stops the running clock timer
 }
 
 const StatsColumnWidth = 200 //Fixed column width for stats panel
 
-func NewCalendarPage(win fyne.Window, repo *db.Repository) *CalendarPage {
+func NewCalendarPage(win fyne.Window, repo *db.Repository, history *UndoHistory) *CalendarPage {
 	c := &CalendarPage{
 		Repo:        repo,
 		Window:      win,
+		History:     history,
 		CurrentDate: time.Now(),
 		ShowDetails: false,
 		DayWidgets:  make(map[string]*DayCell),
@@ -59,6 +103,7 @@ func NewCalendarPage(win fyne.Window, repo *db.Repository) *CalendarPage {
 	c.MonthlyRegularLabel = widget.NewLabelWithStyle("0.00", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
 	c.MonthlyOvertimeLabel = widget.NewLabelWithStyle("0.00", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
 	c.MonthlyTotalLabel = widget.NewLabelWithStyle("0.00", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	c.MonthlyEarningsLabel = widget.NewLabelWithStyle("$0.00", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
 
 	c.ToggleBtn = widget.NewButtonWithIcon("Show Extra Fields", theme.MenuDropDownIcon(), func() {
 		c.ShowDetails = !c.ShowDetails
@@ -68,28 +113,65 @@ func NewCalendarPage(win fyne.Window, repo *db.Repository) *CalendarPage {
 	})
 	c.ToggleBtn.Hide()
 
+	// Full-time only: explanation printed next to Other Paid hours
+	c.OtherPaidDescEntry = widget.NewEntry()
+	c.OtherPaidDescEntry.SetPlaceHolder("Other paid explanation (e.g. Jury duty)")
+	c.OtherPaidDescEntry.Hide()
+
+	// Full-time only: shown when leave taken exceeds the balance
+	c.LeaveWarningLabel = widget.NewLabel("")
+	c.ValidationLabel = widget.NewLabel("")
+	c.ValidationLabel.Importance = widget.DangerImportance
+	c.ValidationLabel.Hide()
+	c.LeaveWarningLabel.Importance = widget.DangerImportance
+	c.LeaveWarningLabel.Wrapping = fyne.TextWrapWord
+	c.LeaveWarningLabel.Hide()
+
+	c.initClock()
+	c.initApproval()
+	c.initUnsaved()
+	c.initUndo()
+	c.initBulkEdit()
+
 	return c
 }
 
 func (c *CalendarPage) BuildUI() fyne.CanvasObject {
 	c.updateMonthLabel()
 
+	// Step to the day before or after the period on screen, Refresh finds its period
 	prevBtn := widget.NewButtonWithIcon("", theme.NavigateBackIcon(), func() {
-		c.CurrentDate = c.CurrentDate.AddDate(0, -1, 0)
-		c.Refresh()
+		c.ConfirmLeave(func() {
+			c.CurrentDate = c.Period.Start.AddDate(0, 0, -1)
+			c.Refresh()
+		})
 	})
 	nextBtn := widget.NewButtonWithIcon("", theme.NavigateNextIcon(), func() {
-		c.CurrentDate = c.CurrentDate.AddDate(0, 1, 0)
-		c.Refresh()
+		c.ConfirmLeave(func() {
+			c.CurrentDate = c.Period.End.AddDate(0, 0, 1)
+			c.Refresh()
+		})
 	})
 
-	saveBtn := widget.NewButtonWithIcon("Save Changes", theme.DocumentSaveIcon(), c.saveData)
+	c.SaveBtn = widget.NewButtonWithIcon("Save Changes", theme.DocumentSaveIcon(), c.saveData)
 	exportBtn := widget.NewButtonWithIcon("Export to PDF", theme.DocumentIcon(), c.exportData)
+	historyBtn := widget.NewButtonWithIcon("History", theme.HistoryIcon(), c.showHistory)
+	holidaysBtn := widget.NewButtonWithIcon("Holidays", theme.CalendarIcon(), func() {
+		showHolidaysDialog(c.Window, c.Repo, func() { c.ConfirmLeave(c.Refresh) })
+	})
 
 	mainHeader := container.NewHBox(
 		prevBtn, c.MonthLabel, nextBtn,
 		layoutSpacer(0),
-		c.ToggleBtn, saveBtn, exportBtn,
+		c.UndoBtn, c.RedoBtn,
+		c.ClockBtn, c.TimerLabel,
+		holidaysBtn, c.BulkBtn, c.ToggleBtn, c.SaveBtn, exportBtn,
+	)
+	statusBar := container.NewHBox(
+		c.StatusLabel, c.ReviewNoteLabel,
+		layoutSpacer(0),
+		c.DirtyLabel, c.AutoSaveCheck,
+		historyBtn, c.SubmitBtn, c.ReviewBtn, c.ReopenBtn,
 	)
 
 	c.Refresh()
@@ -98,19 +180,21 @@ func (c *CalendarPage) BuildUI() fyne.CanvasObject {
 	regularBox := c.createMetricBox("Regular Hours", c.MonthlyRegularLabel)
 	overtimeBox := c.createMetricBox("Overtime Hours", c.MonthlyOvertimeLabel)
 	totalBox := c.createMetricBox("Total Hours", c.MonthlyTotalLabel)
+	earningsBox := c.createMetricBox("Est. Gross Pay", c.MonthlyEarningsLabel)
 
-	footerContainer := container.NewGridWithColumns(3,
+	footerContainer := container.NewGridWithColumns(4,
 		regularBox,
 		overtimeBox,
 		totalBox,
+		earningsBox,
 	)
 
 	return container.NewBorder(
-		mainHeader,
+		container.NewVBox(mainHeader, statusBar, c.SnapshotLabel),
 		nil, nil, nil,
 		container.NewBorder(
 			c.buildWeekHeader(),
-			container.NewPadded(footerContainer),
+			container.NewVBox(c.ValidationLabel, c.LeaveWarningLabel, c.OtherPaidDescEntry, container.NewPadded(footerContainer)),
 			nil, nil,
 			container.NewScroll(c.WeeksContainer),
 		),
@@ -137,6 +221,15 @@ func (c *CalendarPage) buildWeekHeader() fyne.CanvasObject {
 }
 
 func (c *CalendarPage) Refresh() {
+	// Journal the last edits before the cells are rebuilt
+	if err := c.AutoSave.Flush(); err != nil {
+		c.showAutoSaveError(err)
+	}
+	c.loading = true
+	defer func() { c.loading = false }()
+	c.recoveredAt = time.Time{}
+
+	c.Period = models.MonthPeriod(c.CurrentDate.Year(), c.CurrentDate.Month())
 	c.updateMonthLabel()
 	c.WeeksContainer.Objects = nil
 	c.DayWidgets = make(map[string]*DayCell)
@@ -148,23 +241,34 @@ func (c *CalendarPage) Refresh() {
 		return
 	}
 
-	log.Printf("DEBUG: Attempting to load timesheet for Month=%d Year=%d\n", int(c.CurrentDate.Month()), c.CurrentDate.Year())
+	c.Profile = prof
 
 	//No profile set
 	if prof == nil {
+		c.setUndoScope("")
+		c.syncApproval(nil)
+		c.syncClock()
+		c.updateDirty()
 		return
 	}
 
-	c.Profile = prof
-
 	if c.Profile.Type == models.TypeFullTime {
 		c.ToggleBtn.Show()
+		c.OtherPaidDescEntry.Show()
 	} else {
 		c.ToggleBtn.Hide()
+		c.OtherPaidDescEntry.Hide()
 		c.ShowDetails = false
 	}
 
-	existingSheet, err := c.Repo.GetTimesheetByDate(int(c.CurrentDate.Month()), c.CurrentDate.Year())
+	// Saved sheets keep their own period, new ones follow the profile's pay period
+	period, existingSheet, err := c.Repo.ResolvePeriod(c.Profile, c.CurrentDate)
+	if err == nil {
+		c.Period = period
+		c.updateMonthLabel()
+	}
+	c.setUndoScope(sheetScope(c.Profile.ID, c.Period))
+	log.Printf("DEBUG: Attempting to load timesheet for %s\n", c.Period.Label())
 
 	if err != nil {
 		log.Println("DEBUG: Critical DB Error:", err)
@@ -174,66 +278,94 @@ func (c *CalendarPage) Refresh() {
 		log.Printf("DEBUG: Loaded Timesheet, found %d entries", len(existingSheet.Entries))
 	}
 
-	// Date Math
-	year, month, _ := c.CurrentDate.Date()
-	firstOfMonth := time.Date(year, month, 1, 0, 0, 0, 0, time.Local)
-	daysInMonth := time.Date(year, month+1, 0, 0, 0, 0, 0, time.Local).Day()
+	// Days of the first workweek that belong to earlier sheets
+	c.CarryIn, c.carryInErr = c.Repo.GetCarryInEntries(c.Profile.ID, c.Period.Start)
+	if c.carryInErr != nil {
+		c.CarryIn = nil
+		dialog.ShowError(fmt.Errorf("failed to load the earlier days of the first week, weekly overtime is wrong and saving is blocked: %v", c.carryInErr), c.Window)
+	}
+
+	c.Holidays, err = c.Repo.GetHolidaysInPeriod(c.Period)
+	if err != nil {
+		c.Holidays = nil
+		dialog.ShowError(fmt.Errorf("failed to load holidays, closed days are not marked: %v", err), c.Window)
+	}
+
+	// Leave balances before this period's usage
+	c.LeavePeriod = nil
+	if c.Profile.Type == models.TypeFullTime {
+		c.LeavePeriod, err = c.Repo.GetLeavePeriod(c.Profile.ID, c.Period)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("failed to load leave balances, low balance warnings are off: %v", err), c.Window)
+		}
+	}
+
+	c.OtherPaidDescEntry.SetText("")
+	if existingSheet != nil {
+		c.OtherPaidDescEntry.SetText(existingSheet.OtherPaidDescription)
+	}
 
 	var currentWeekCells []fyne.CanvasObject
-	var currentWeekData []models.DailyEntry
 	var grandTotalWork float64
 
 	// Padding
-	startOffset := int(firstOfMonth.Weekday()) - 1
+	startOffset := int(c.Period.Start.Weekday()) - 1
 	if startOffset < 0 {
 		startOffset = 6
 	}
 
 	for i := 0; i < startOffset; i++ {
-		currentWeekCells = append(currentWeekCells, layoutSpacer(10))
-		currentWeekData = append(currentWeekData, models.DailyEntry{})
+		padDate := c.Period.Start.AddDate(0, 0, i-startOffset)
+		currentWeekCells = append(currentWeekCells, carryInCell(padDate, c.CarryIn[padDate.Format("2006-01-02")]))
 	}
 
 	// Optimization: Define callback once
-	onInputChanged := func() { c.recalculateLive() }
+	onInputChanged := func(cell *DayCell) {
+		c.cellEdited(cell)
+		if c.replaying {
+			return
+		}
+		c.recalculateLive()
+		c.edited()
+	}
+
+	// Holidays never change a submitted or approved sheet
+	locked := existingSheet != nil && existingSheet.Locked()
 
 	// Render loop
-	for day := 1; day <= daysInMonth; day++ {
-		date := time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+	for date := c.Period.Start; !date.After(c.Period.End); date = date.AddDate(0, 0, 1) {
 		dateStr := date.Format("2006-01-02")
 
 		var entry models.DailyEntry
+		autoFilled, saved := false, false
 		if existingSheet != nil && len(existingSheet.Entries) > 0 {
-			if val, ok := existingSheet.Entries[dateStr]; ok {
-				entry = val
-			}
+			entry, saved = existingSheet.Entries[dateStr]
 		} else {
 			// Schedule Auto-fill
-			dayOfWeek := int(date.Weekday()) - 1
-			if dayOfWeek < 0 {
-				dayOfWeek = 6
-			}
-			if sched, ok := c.Profile.Schedule[dayOfWeek]; ok && sched.Active {
-				for _, r := range sched.Ranges {
-					entry.HoursWorked += CalculateDailyHours(r.Start + "-" + r.End)
-				}
-			}
+			sched := c.scheduleFor(date)
+			entry.HoursWorked = sched.TotalHours()
+			entry.OvernightHours = sched.OvernightHours()
+			autoFilled = true
 		}
 		entry.Date = dateStr
 
-		// Create widget
-		cell := NewDayCell(day, entry, c.Profile.Type, onInputChanged)
+		// Holidays and closures override the schedule, saved days keep what was saved
+		if h, ok := c.Holidays[dateStr]; ok && !saved && !locked {
+			entry = c.applyHoliday(entry, h, date, autoFilled)
+		}
+
+		// Create widget. Part-time days with a secondary FOAP in force get a split input
+		split := c.Profile.Type == models.TypePartTime && c.Profile.TermsOn(dateStr).SecondaryAccounting != nil
+		cell := NewDayCell(date.Day(), entry, c.Profile.Type, split, onInputChanged)
 		cell.SetExtrasVisible(c.ShowDetails)
 		c.DayWidgets[dateStr] = cell
 
 		currentWeekCells = append(currentWeekCells, cell.CanvasObj)
-		currentWeekData = append(currentWeekData, entry)
 		grandTotalWork += entry.HoursWorked
 
 		if len(currentWeekCells) == 7 {
-			c.renderWeekRow(currentWeekCells, currentWeekData)
+			c.renderWeekRow(currentWeekCells)
 			currentWeekCells = nil
-			currentWeekData = nil
 		}
 	}
 
@@ -241,85 +373,49 @@ func (c *CalendarPage) Refresh() {
 	if len(currentWeekCells) > 0 {
 		for len(currentWeekCells) < 7 {
 			currentWeekCells = append(currentWeekCells, layoutSpacer(10))
-			currentWeekData = append(currentWeekData, models.DailyEntry{})
 		}
-		c.renderWeekRow(currentWeekCells, currentWeekData)
+		c.renderWeekRow(currentWeekCells)
 	}
 
 	c.WeeksContainer.Refresh()
-	// Calculate monthly totals for footer
-	var monthlyGrandTotal, monthlyOT float64
-
-	// Calculate weekly overtime and sum it up
-	year, month, _ = c.CurrentDate.Date()
-	firstOfMonth = time.Date(year, month, 1, 0, 0, 0, 0, time.Local)
-	daysInMonth = time.Date(year, month+1, 0, 0, 0, 0, 0, time.Local).Day()
-
-	startOffset = int(firstOfMonth.Weekday()) - 1
-	if startOffset < 0 {
-		startOffset = 6
-	}
+	c.syncApproval(existingSheet)
 
-	var currentWeekDates []string
-	// Add padding days
-	for i := 0; i < startOffset; i++ {
-		currentWeekDates = append(currentWeekDates, "")
+	// What was loaded is the saved state, then any draft left unsaved goes on top
+	c.savedOtherDesc = c.OtherPaidDescEntry.Text
+	c.loading = false
+	if !c.locked() {
+		c.restoreDraft()
 	}
+	c.updateDirty()
 
-	// Process each day
-	for day := 1; day <= daysInMonth; day++ {
-		date := time.Date(year, month, day, 0, 0, 0, 0, time.Local)
-		dateStr := date.Format("2006-01-02")
-		currentWeekDates = append(currentWeekDates, dateStr)
-
-		// When we have a full week, calculate overtime for that week
-		if len(currentWeekDates) == 7 {
-			var weekTotal float64
-			for _, ds := range currentWeekDates {
-				if ds != "" {
-					if cell, ok := c.DayWidgets[ds]; ok {
-						data := cell.GetData()
-						dayTotal := data.HoursWorked + data.SickLeave + data.Vacation + data.Holiday + data.CompTimeTaken + data.OtherPaid
-						weekTotal += dayTotal
-						monthlyGrandTotal += dayTotal
-					}
-				}
-			}
-
-			threshold := c.Profile.Type.OvertimeThreshold()
-			if weekTotal > threshold {
-				monthlyOT += weekTotal - threshold
-			}
+	// Weekly stats and footer totals
+	c.recalculateLive()
+	c.syncClock()
+}
 
-			currentWeekDates = nil
-		}
-	}
+// The profile's schedule for a date's weekday
+func (c *CalendarPage) scheduleFor(date time.Time) models.DaySchedule {
+	return c.Profile.Schedule[weekdayIndex(date)]
+}
 
-	// Handle partial week at end of month
-	if len(currentWeekDates) > 0 {
-		var weekTotal float64
-		for _, ds := range currentWeekDates {
-			if ds != "" {
-				if cell, ok := c.DayWidgets[ds]; ok {
-					data := cell.GetData()
-					dayTotal := data.HoursWorked + data.SickLeave + data.Vacation + data.Holiday + data.CompTimeTaken + data.OtherPaid
-					weekTotal += dayTotal
-					monthlyGrandTotal += dayTotal
-				}
-			}
-		}
+// Fill a holiday or closure day. Full-time staff are paid their scheduled hours as
+// Holiday, part-time and work-study staff are not paid when the college is closed
+func (c *CalendarPage) applyHoliday(entry models.DailyEntry, h models.Holiday, date time.Time, autoFilled bool) models.DailyEntry {
+	entry.Closure = h.Name
 
-		threshold := c.Profile.Type.OvertimeThreshold()
-		if weekTotal > threshold {
-			monthlyOT += weekTotal - threshold
+	if c.Profile.Type == models.TypeFullTime {
+		// Leave days the employee already filled in by hand
+		if autoFilled || entry.TotalPaid() == 0 {
+			entry.HoursWorked = 0
+			entry.OvernightHours = 0
+			entry.Holiday = c.scheduleFor(date).TotalHours()
 		}
+		return entry
 	}
 
-	monthlyRegular := monthlyGrandTotal - monthlyOT
-
-	c.MonthlyRegularLabel.SetText(fmt.Sprintf("%.2f hrs", monthlyRegular))
-	c.MonthlyOvertimeLabel.SetText(fmt.Sprintf("%.2f hrs", monthlyOT))
-	c.MonthlyTotalLabel.SetText(fmt.Sprintf("%.2f hrs", monthlyGrandTotal))
+	entry.HoursWorked = 0
+	entry.OvernightHours = 0
+	return entry
 }
 
 // createMetricBox creates a compact purple box with rounded corners for a metric
@@ -350,15 +446,15 @@ func (c *CalendarPage) createMetricBox(title string, valueLabel *widget.Label) f
 	return container.NewStack(bg, paddedContent)
 }
 
-// renderWeekRow builds the row and calculates initial stats immediately
-func (c *CalendarPage) renderWeekRow(cells []fyne.CanvasObject, data []models.DailyEntry) {
+// renderWeekRow builds the row with an empty stats card. recalculateLive fills the
+// cards from rules.EvaluatePeriod, so weeks count the carry-in days
+func (c *CalendarPage) renderWeekRow(cells []fyne.CanvasObject) {
 	dayGrid := container.NewGridWithColumns(7)
 	for _, obj := range cells {
 		dayGrid.Add(obj)
 	}
 
-	// Calculate stats and create table
-	statsTable := generateWeeklyStatsTable(data, c.Profile.Type)
+	statsTable := generateWeeklyStatsTable(rules.WeekResult{}, c.Profile.Type)
 	c.WeeklyStatsContainers = append(c.WeeklyStatsContainers, statsTable)
 
 	rightContainer := c.makeFixedContainer(statsTable)
@@ -368,143 +464,131 @@ func (c *CalendarPage) renderWeekRow(cells []fyne.CanvasObject, data []models.Da
 	c.WeeksContainer.Add(widget.NewSeparator())
 }
 
+// Scrape the current cell values into an entries map
+func (c *CalendarPage) collectEntries() map[string]models.DailyEntry {
+	entries := make(map[string]models.DailyEntry, len(c.DayWidgets))
+	for dateStr, cell := range c.DayWidgets {
+		entries[dateStr] = cell.GetData()
+	}
+	return entries
+}
+
 // recalculateLive scrapes UI widgets and updates text
 func (c *CalendarPage) recalculateLive() {
-	year, month, _ := c.CurrentDate.Date()
-	daysInMonth := time.Date(year, month+1, 0, 0, 0, 0, 0, time.Local).Day()
-	firstOfMonth := time.Date(year, month, 1, 0, 0, 0, 0, time.Local)
-
-	startOffset := int(firstOfMonth.Weekday()) - 1
-	if startOffset < 0 {
-		startOffset = 6
+	if c.Profile == nil {
+		return
 	}
 
-	var currentWeekData []models.DailyEntry
-	weekIndex := 0
-	var grandTotalWork float64
+	entries := c.collectEntries()
+	weeks := rules.EvaluatePeriod(entries, c.CarryIn, c.Period, rules.PolicyFor(c.Profile.Type))
+	c.checkLeaveBalances(entries, weeks)
 
-	for i := 0; i < startOffset; i++ {
-		currentWeekData = append(currentWeekData, models.DailyEntry{})
+	if err := c.validateCells(); err != nil {
+		c.ValidationLabel.SetText(err.Error())
+		c.ValidationLabel.Show()
+	} else {
+		c.ValidationLabel.Hide()
 	}
 
-	for day := 1; day <= daysInMonth; day++ {
-		date := time.Date(year, month, day, 0, 0, 0, 0, time.Local)
-		dateStr := date.Format("2006-01-02")
-
-		var d models.DailyEntry
-		if cell, ok := c.DayWidgets[dateStr]; ok {
-			d = cell.GetData()
-		}
-
-		currentWeekData = append(currentWeekData, d)
-		grandTotalWork += d.HoursWorked
-
-		// FIX: Strictly check for full week to increment
-		if len(currentWeekData) == 7 {
-			if weekIndex < len(c.WeeklyStatsContainers) {
-				// Find the parent row container and update it
-				newStatsTable := generateWeeklyStatsTable(currentWeekData, c.Profile.Type)
-				// Replace the container's content
-				if card, ok := c.WeeklyStatsContainers[weekIndex].(*widget.Card); ok {
-					card.SetContent(newStatsTable.(*widget.Card).Content)
-				}
+	// Calculate period totals for footer - overtime calculated per week
+	var monthlyGrandTotal, monthlyOT float64
+	for weekIndex, week := range weeks {
+		if weekIndex < len(c.WeeklyStatsContainers) {
+			// Replace the card's content in place
+			newStatsTable := generateWeeklyStatsTable(week.Result, c.Profile.Type)
+			if card, ok := c.WeeklyStatsContainers[weekIndex].(*widget.Card); ok {
+				card.SetContent(newStatsTable.(*widget.Card).Content)
 			}
-			currentWeekData = nil
-			weekIndex++
 		}
-	}
 
-	// Final partial week
-	if len(currentWeekData) > 0 && weekIndex < len(c.WeeklyStatsContainers) {
-		newStatsTable := generateWeeklyStatsTable(currentWeekData, c.Profile.Type)
-		if card, ok := c.WeeklyStatsContainers[weekIndex].(*widget.Card); ok {
-			card.SetContent(newStatsTable.(*widget.Card).Content)
-		}
+		monthlyGrandTotal += week.Result.Total
+		monthlyOT += week.Result.Overtime
 	}
 
-	// Calculate monthly totals for footer - overtime calculated per week
-	var monthlyGrandTotal, monthlyOT float64
+	monthlyRegular := monthlyGrandTotal - monthlyOT
 
-	// Reset and recalculate weekly overtime
-	currentWeekData = nil
-	for i := 0; i < startOffset; i++ {
-		currentWeekData = append(currentWeekData, models.DailyEntry{})
-	}
+	c.MonthlyRegularLabel.SetText(fmt.Sprintf("%.2f hrs", monthlyRegular))
+	c.MonthlyOvertimeLabel.SetText(fmt.Sprintf("%.2f hrs", monthlyOT))
+	c.MonthlyTotalLabel.SetText(fmt.Sprintf("%.2f hrs", monthlyGrandTotal))
 
-	for day := 1; day <= daysInMonth; day++ {
-		date := time.Date(year, month, day, 0, 0, 0, 0, time.Local)
-		dateStr := date.Format("2006-01-02")
+	// Pay estimate from the same rules
+	ts := models.Timesheet{Entries: entries, CarryIn: c.CarryIn}
+	ts.SetPeriod(c.Period)
+	earnings := rules.EstimateEarnings(c.Profile, &ts, rules.PolicyFor(c.Profile.Type))
+	switch {
+	case earnings.CompBanked && earnings.OvertimeHours > 0:
+		c.MonthlyEarningsLabel.SetText(fmt.Sprintf("$%.2f (OT banked)", earnings.Gross))
+	case earnings.OvertimePay > 0:
+		c.MonthlyEarningsLabel.SetText(fmt.Sprintf("$%.2f ($%.2f OT)", earnings.Gross, earnings.OvertimePay))
+	default:
+		c.MonthlyEarningsLabel.SetText(fmt.Sprintf("$%.2f", earnings.Gross))
+	}
+}
 
-		var d models.DailyEntry
-		if cell, ok := c.DayWidgets[dateStr]; ok {
-			d = cell.GetData()
-		}
+// Warn about the first day each leave balance would go below zero
+func (c *CalendarPage) checkLeaveBalances(entries map[string]models.DailyEntry, weeks []rules.Week) {
+	dates := make([]string, 0, len(entries))
+	for date := range entries {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
 
-		currentWeekData = append(currentWeekData, d)
+	var earned float64
+	for _, w := range weeks {
+		earned += w.Result.CompTimeEarned
+	}
 
-		// When we have a full week, calculate overtime for that week
-		if len(currentWeekData) == 7 {
-			var weekTotal float64
-			for _, entry := range currentWeekData {
-				dayTotal := entry.HoursWorked + entry.SickLeave + entry.Vacation + entry.Holiday + entry.CompTimeTaken + entry.OtherPaid
-				weekTotal += dayTotal
-				monthlyGrandTotal += dayTotal
-			}
+	var warnings []string
+	for _, t := range models.LeaveTypes {
+		line, ok := c.LeavePeriod[t]
+		if !ok {
+			continue
+		}
 
-			threshold := c.Profile.Type.OvertimeThreshold()
-			if weekTotal > threshold {
-				monthlyOT += weekTotal - threshold
+		available := line.Opening + line.Accrued
+		if t == models.LeaveComp {
+			available += earned
+		}
+		for _, date := range dates {
+			available -= entries[date].LeaveUsed(t)
+			if available < 0 {
+				day, _ := time.Parse("2006-01-02", date)
+				warnings = append(warnings, fmt.Sprintf("%s balance goes negative on %s (%.2f hrs)",
+					leaveTypeLabels[t], day.Format("Jan 2"), available))
+				break
 			}
-
-			currentWeekData = nil
 		}
 	}
 
-	// Handle partial week at end of month
-	if len(currentWeekData) > 0 {
-		var weekTotal float64
-		for _, entry := range currentWeekData {
-			dayTotal := entry.HoursWorked + entry.SickLeave + entry.Vacation + entry.Holiday + entry.CompTimeTaken + entry.OtherPaid
-			weekTotal += dayTotal
-			monthlyGrandTotal += dayTotal
-		}
+	if len(warnings) == 0 {
+		c.LeaveWarningLabel.Hide()
+		return
+	}
+	c.LeaveWarningLabel.SetText(strings.Join(warnings, "\n"))
+	c.LeaveWarningLabel.Show()
+}
 
-		threshold := c.Profile.Type.OvertimeThreshold()
-		if weekTotal > threshold {
-			monthlyOT += weekTotal - threshold
-		}
+// Padding cell for a day before the period, showing hours already reported on an earlier sheet
+func carryInCell(date time.Time, entry models.DailyEntry) fyne.CanvasObject {
+	hours := rules.HoursByType(entry)
+	var total float64
+	for _, h := range hours {
+		total += h
+	}
+	if total == 0 {
+		return layoutSpacer(10)
 	}
 
-	monthlyRegular := monthlyGrandTotal - monthlyOT
+	dateLabel := widget.NewLabelWithStyle(date.Format("Jan 2"), fyne.TextAlignCenter, fyne.TextStyle{Italic: true})
+	dateLabel.Importance = widget.LowImportance
+	hoursLabel := widget.NewLabelWithStyle(fmt.Sprintf("%.2f hrs", total), fyne.TextAlignCenter, fyne.TextStyle{Italic: true})
+	hoursLabel.Importance = widget.LowImportance
 
-	c.MonthlyRegularLabel.SetText(fmt.Sprintf("%.2f hrs", monthlyRegular))
-	c.MonthlyOvertimeLabel.SetText(fmt.Sprintf("%.2f hrs", monthlyOT))
-	c.MonthlyTotalLabel.SetText(fmt.Sprintf("%.2f hrs", monthlyGrandTotal))
+	return container.NewVBox(dateLabel, hoursLabel)
 }
 
 // Shared logic for stats table generation
-func generateWeeklyStatsTable(data []models.DailyEntry, empType models.EmployeeType) fyne.CanvasObject {
-	var tWork, tSick, tVac, tHol, tComp, tOther float64
-	for _, d := range data {
-		tWork += d.HoursWorked
-		tSick += d.SickLeave
-		tVac += d.Vacation
-		tHol += d.Holiday
-		tComp += d.CompTimeTaken
-		tOther += d.OtherPaid
-	}
-
-	totalCompensated := tWork + tSick + tVac + tHol + tComp + tOther
-
-	threshold := empType.OvertimeThreshold()
-	var otHours, regularHours float64
-	if totalCompensated > threshold {
-		otHours = totalCompensated - threshold
-		regularHours = threshold
-	} else {
-		regularHours = totalCompensated
-	}
-
+func generateWeeklyStatsTable(res rules.WeekResult, empType models.EmployeeType) fyne.CanvasObject {
 	// Create table header
 	headerBreakdown := widget.NewLabelWithStyle("Breakdown", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
 	headerRegular := widget.NewLabelWithStyle("Regular", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
@@ -516,86 +600,79 @@ func generateWeeklyStatsTable(data []models.DailyEntry, empType models.EmployeeT
 
 	// Add breakdown rows for full-time
 	if empType == models.TypeFullTime {
-		if tWork > 0 {
-			rows = append(rows, container.NewGridWithColumns(3,
-				widget.NewLabel("Work"),
-				widget.NewLabelWithStyle(fmt.Sprintf("%.2f", tWork), fyne.TextAlignCenter, fyne.TextStyle{}),
-				widget.NewLabel(""),
-			))
-		}
-		if tSick > 0 {
-			rows = append(rows, container.NewGridWithColumns(3,
-				widget.NewLabel("Sick Leave"),
-				widget.NewLabelWithStyle(fmt.Sprintf("%.2f", tSick), fyne.TextAlignCenter, fyne.TextStyle{}),
-				widget.NewLabel(""),
-			))
-		}
-		if tVac > 0 {
-			rows = append(rows, container.NewGridWithColumns(3,
-				widget.NewLabel("Vacation"),
-				widget.NewLabelWithStyle(fmt.Sprintf("%.2f", tVac), fyne.TextAlignCenter, fyne.TextStyle{}),
-				widget.NewLabel(""),
-			))
-		}
-		if tHol > 0 {
-			rows = append(rows, container.NewGridWithColumns(3,
-				widget.NewLabel("Holiday"),
-				widget.NewLabelWithStyle(fmt.Sprintf("%.2f", tHol), fyne.TextAlignCenter, fyne.TextStyle{}),
-				widget.NewLabel(""),
-			))
+		labels := map[rules.HourType]string{
+			rules.Worked:    "Work",
+			rules.Sick:      "Sick Leave",
+			rules.Vacation:  "Vacation",
+			rules.Holiday:   "Holiday",
+			rules.CompTaken: "Comp Time",
+			rules.OtherPaid: "Other Paid",
 		}
-		if tComp > 0 {
-			rows = append(rows, container.NewGridWithColumns(3,
-				widget.NewLabel("Comp Time"),
-				widget.NewLabelWithStyle(fmt.Sprintf("%.2f", tComp), fyne.TextAlignCenter, fyne.TextStyle{}),
-				widget.NewLabel(""),
-			))
-		}
-		if tOther > 0 {
-			rows = append(rows, container.NewGridWithColumns(3,
-				widget.NewLabel("Other Paid"),
-				widget.NewLabelWithStyle(fmt.Sprintf("%.2f", tOther), fyne.TextAlignCenter, fyne.TextStyle{}),
-				widget.NewLabel(""),
-			))
+		for _, ht := range rules.HourTypes {
+			if res.Hours[ht] > 0 {
+				rows = append(rows, container.NewGridWithColumns(3,
+					widget.NewLabel(labels[ht]),
+					widget.NewLabelWithStyle(fmt.Sprintf("%.2f", res.Hours[ht]), fyne.TextAlignCenter, fyne.TextStyle{}),
+					widget.NewLabel(""),
+				))
+			}
 		}
 	}
 
+	// Hours from the previous sheet that count toward this week's threshold
+	if res.CarriedIn > 0 {
+		rows = append(rows, container.NewGridWithColumns(3,
+			widget.NewLabelWithStyle("Carried In", fyne.TextAlignLeading, fyne.TextStyle{Italic: true}),
+			widget.NewLabelWithStyle(fmt.Sprintf("%.2f", res.CarriedIn), fyne.TextAlignCenter, fyne.TextStyle{Italic: true}),
+			widget.NewLabel(""),
+		))
+	}
+
+	// Night shift hours moved across the week's edges by the split-at-midnight rule
+	if res.Shifted != 0 {
+		rows = append(rows, container.NewGridWithColumns(3,
+			widget.NewLabelWithStyle("Night Shift", fyne.TextAlignLeading, fyne.TextStyle{Italic: true}),
+			widget.NewLabelWithStyle(fmt.Sprintf("%+.2f", res.Shifted), fyne.TextAlignCenter, fyne.TextStyle{Italic: true}),
+			widget.NewLabel(""),
+		))
+	}
+
 	// Add totals row with separator
 	rows = append(rows, widget.NewSeparator())
 	rows = append(rows, container.NewGridWithColumns(3,
 		widget.NewLabelWithStyle("Weekly Total", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
-		widget.NewLabelWithStyle(fmt.Sprintf("%.2f", regularHours), fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
-		widget.NewLabelWithStyle(fmt.Sprintf("%.2f", otHours), fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
+		widget.NewLabelWithStyle(fmt.Sprintf("%.2f", res.Regular), fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
+		widget.NewLabelWithStyle(fmt.Sprintf("%.2f", res.Overtime), fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
 	))
 
+	// Comp time banked instead of overtime pay
+	if res.CompTimeEarned > 0 {
+		rows = append(rows, container.NewGridWithColumns(3,
+			widget.NewLabel("Comp Earned"),
+			widget.NewLabel(""),
+			widget.NewLabelWithStyle(fmt.Sprintf("%.2f", res.CompTimeEarned), fyne.TextAlignCenter, fyne.TextStyle{}),
+		))
+	}
+
 	tableContent := container.NewVBox(rows...)
 	return widget.NewCard("", "", tableContent)
 }
 
-// ... updateMonthLabel, saveData, exportData remain the same ...
+// Header shows the month, or the period's dates when it is not a calendar month
 func (c *CalendarPage) updateMonthLabel() {
-	c.MonthLabel.SetText(c.CurrentDate.Format("January 2006"))
+	if c.Period.Start.IsZero() {
+		c.MonthLabel.SetText(c.CurrentDate.Format("January 2006"))
+		return
+	}
+	c.MonthLabel.SetText(c.Period.Label())
 }
 
 func (c *CalendarPage) saveData() {
-	entries := make(map[string]models.DailyEntry)
-	var totalWorked float64
-	for dateStr, cell := range c.DayWidgets {
-		data := cell.GetData()
-		entries[dateStr] = data
-		totalWorked += data.HoursWorked
+	if c.Profile == nil {
+		return
 	}
 
-	log.Printf("DEBUG: Saving Timesheet -> Month: %d, Year: %d, Total Entries: %d, Total Hours: %.2f\n",
-		int(c.CurrentDate.Month()), c.CurrentDate.Year(), len(entries), totalWorked)
-
-	ts := models.Timesheet{
-		Month:       int(c.CurrentDate.Month()),
-		Year:        c.CurrentDate.Year(),
-		Entries:     entries,
-		TotalWorked: totalWorked,
-	}
-	if err := c.Repo.SaveTimesheet(ts); err != nil {
+	if err := c.storeTimesheet(); err != nil {
 		log.Println("DEBUG: Save FAILED:", err)
 		dialog.ShowError(err, c.Window)
 		return
@@ -604,6 +681,101 @@ func (c *CalendarPage) saveData() {
 	log.Println("DEBUG: Save SUCCESS")
 }
 
+// Build the sheet from the cells, summarize it and write it to the DB. Refuses while
+// any cell is invalid; valid cells are shown rounded as saved
+func (c *CalendarPage) storeTimesheet() error {
+	if err := c.validateCells(); err != nil {
+		return err
+	}
+	for _, cell := range c.DayWidgets {
+		cell.ApplyRounding()
+	}
+	if err := c.storeEntries(c.collectEntries(), ""); err != nil {
+		return err
+	}
+	c.markSaved()
+	return nil
+}
+
+// Check every day on screen, highlighting bad inputs. The error names the first bad
+// day and how many need fixing
+func (c *CalendarPage) validateCells() error {
+	dates := make([]string, 0, len(c.DayWidgets))
+	for date := range c.DayWidgets {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	var first error
+	bad := 0
+	for _, date := range dates {
+		err := c.DayWidgets[date].Validate()
+		if err == nil {
+			continue
+		}
+		bad++
+		if first == nil {
+			day, _ := time.Parse("2006-01-02", date)
+			first = fmt.Errorf("%s: %v", day.Format("Mon Jan 2"), err)
+		}
+	}
+
+	if bad == 0 {
+		return nil
+	}
+	return fmt.Errorf("fix %d day(s) with invalid hours before saving or exporting. %v", bad, first)
+}
+
+// Summarize entries for the period on screen and write them to the DB. The note is
+// recorded in the revision history
+func (c *CalendarPage) storeEntries(entries map[string]models.DailyEntry, note string) error {
+	if c.carryInErr != nil {
+		return fmt.Errorf("cannot save, the earlier days of the first week did not load: %v", c.carryInErr)
+	}
+
+	ts := models.Timesheet{
+		ProfileID: c.Profile.ID,
+		Entries:   entries,
+		CarryIn:   c.CarryIn,
+		SaveNote:  note,
+	}
+	ts.SetPeriod(c.Period)
+
+	// Freeze the profile as of this save for later reports
+	snapshot := *c.Profile
+	ts.ProfileSnapshot = &snapshot
+
+	if err := validation.ValidateEntries(entries); err != nil {
+		return err
+	}
+
+	// Weekly rollups, totals and estimated pay are stored with the sheet
+	policy := rules.PolicyFor(c.Profile.Type)
+	rules.Summarize(&ts, policy)
+	ts.GrossEarnings = rules.EstimateEarnings(c.Profile, &ts, policy).Gross
+	if c.Profile.Type == models.TypeFullTime {
+		ts.OtherPaidDescription = c.OtherPaidDescEntry.Text
+	}
+
+	log.Printf("DEBUG: Saving Timesheet -> Period: %s to %s, Total Entries: %d, Total Hours: %.2f\n",
+		ts.PeriodStart, ts.PeriodEnd, len(entries), ts.TotalWorked)
+
+	// Work-study balance carries forward from the previous sheet
+	if c.Profile.Type == models.TypeWorkStudy {
+		if err := c.Repo.ApplyWorkStudyBalance(c.Profile, &ts); err != nil {
+			return err
+		}
+	}
+
+	// Saving clears the journal, a draft written after it would be stale
+	c.AutoSave.Cancel()
+	if err := c.Repo.SaveTimesheet(ts); err != nil {
+		c.edited()
+		return err
+	}
+	return nil
+}
+
 func (c *CalendarPage) makeFixedContainer(obj fyne.CanvasObject) fyne.CanvasObject {
 	// Create transparent spacer
 	spacer := canvas.NewRectangle(color.Transparent)
@@ -614,42 +786,52 @@ func (c *CalendarPage) makeFixedContainer(obj fyne.CanvasObject) fyne.CanvasObje
 }
 
 func (c *CalendarPage) exportData() {
-	// First save current data
-	c.saveData()
-
-	// Get current timesheet
-	ts, err := c.Repo.GetTimesheetByDate(int(c.CurrentDate.Month()), c.CurrentDate.Year())
-	if err != nil {
-		dialog.ShowError(fmt.Errorf("failed to load timesheet: %v", err), c.Window)
+	if c.Profile == nil {
+		dialog.ShowInformation("No Profile", "Create a profile before exporting", c.Window)
 		return
 	}
 
-	// Show file save dialog
-	saveDialog := dialog.NewFileSave(func(uc fyne.URIWriteCloser, err error) {
-		if err != nil {
+	// First save current data, locked sheets export as saved. Nothing is previewed
+	// unless the save went through
+	if !c.locked() {
+		if err := c.storeTimesheet(); err != nil {
 			dialog.ShowError(err, c.Window)
 			return
 		}
-		if uc == nil {
-			return // User cancelled
-		}
-		defer uc.Close()
+	}
 
-		// Generate PDF
-		err = pdfgen.GenerateTimesheet(c.Profile, ts, uc.URI().Path())
-		if err != nil {
-			dialog.ShowError(fmt.Errorf("failed to generate PDF: %v", err), c.Window)
+	// Get current timesheet
+	ts, err := c.Repo.GetTimesheetForPeriod(c.Profile.ID, c.Period)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("failed to load timesheet: %v", err), c.Window)
+		return
+	}
+	if ts == nil {
+		dialog.ShowInformation("Not Saved", "Save the timesheet first", c.Window)
+		return
+	}
+	ts.CarryIn = c.CarryIn
+
+	if c.Profile.Type == models.TypeWorkStudy {
+		if err := c.Repo.ApplyWorkStudyBalance(ts.ReportProfile(c.Profile), ts); err != nil {
+			dialog.ShowError(fmt.Errorf("failed to calculate balance: %v", err), c.Window)
 			return
 		}
+	}
 
-		dialog.ShowInformation("Success", "PDF exported successfully!", c.Window)
-	}, c.Window)
+	// Generate PDF to a temporary file, the preview saves it where the user picks
+	tmp, err := os.CreateTemp("", "timesheet-*.pdf")
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("failed to generate PDF: %v", err), c.Window)
+		return
+	}
+	tmp.Close()
+	if err := pdfgen.GenerateTimesheet(c.Profile, ts, tmp.Name()); err != nil {
+		os.Remove(tmp.Name())
+		dialog.ShowError(fmt.Errorf("failed to generate PDF: %v", err), c.Window)
+		return
+	}
 
-	// Set default filename
-	defaultName := fmt.Sprintf("timesheet_%s_%d.pdf",
-		c.CurrentDate.Format("January"),
-		c.CurrentDate.Year())
-	saveDialog.SetFileName(defaultName)
-	saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{".pdf"}))
-	saveDialog.Show()
+	defaultName := fmt.Sprintf("timesheet_%s.pdf", c.Period.FileLabel())
+	showPDFPreview(c.Window, c.Period.Label(), tmp.Name(), defaultName)
 }
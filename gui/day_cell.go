@@ -3,10 +3,10 @@ package gui
 import (
 	"fmt"
 	"strconv"
-
-	//"strconv"
+	"strings"
 
 	"calendar_utility_node_for_timesheets/models"
+	"calendar_utility_node_for_timesheets/validation"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
@@ -22,6 +22,26 @@ type DayCell struct {
 	//Input
 	WorkedEntry *widget.Entry
 
+	// Hours of the day charged to the secondary FOAP, nil without one
+	SecondaryEntry *widget.Entry
+
+	// Holiday or closure name, empty on a normal day
+	Closure string
+	closed  bool // Closure with no paid hours, input stays disabled
+
+	// Clock-in/clock-out times for the day
+	Punches    []models.TimeRange
+	PunchLabel *widget.Label
+
+	// Hours worked after midnight on a shift that started this day
+	Overnight float64
+
+	// The day as last loaded or saved, for unsaved-changes tracking
+	saved models.DailyEntry
+
+	// The day as of the last change seen by the undo history
+	undoBase models.DailyEntry
+
 	ExtrasContainer *fyne.Container
 
 	// Full time inputs
@@ -32,27 +52,64 @@ type DayCell struct {
 	OtherEntry    *widget.Entry
 }
 
-func NewDayCell(dayNum int, data models.DailyEntry, empType models.EmployeeType, onChanged func()) *DayCell {
+// split adds an input for hours charged to the secondary accounting codes
+// onChanged is called with the cell after any input changes
+func NewDayCell(dayNum int, data models.DailyEntry, empType models.EmployeeType, split bool, onChanged func(*DayCell)) *DayCell {
 	//Initialize cell
 	cell := &DayCell{
-		DateStr: data.Date,
+		DateStr:   data.Date,
+		Closure:   data.Closure,
+		Overnight: data.OvernightHours,
+	}
+	changed := func() {
+		if onChanged != nil {
+			onChanged(cell)
+		}
 	}
 
 	// Generalized input (hurs worked that date)
-	cell.WorkedEntry = makeEntry(data.HoursWorked, onChanged)
+	cell.WorkedEntry = makeEntry(data.HoursWorked, changed)
+	if split {
+		cell.SecondaryEntry = makeEntry(data.SecondaryHours, changed)
+		cell.SecondaryEntry.SetPlaceHolder("2nd")
+	}
+
+	// Punch times, only shown once the day has been clocked
+	cell.PunchLabel = widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Italic: true})
+	cell.PunchLabel.Wrapping = fyne.TextWrapWord
+	cell.setPunchLabel(data.Punches)
 
 	//Label for day number
 	dayLabel := widget.NewLabelWithStyle(fmt.Sprintf("%d", dayNum), fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
 
+	// Holiday name next to the day number
+	var closureLabel fyne.CanvasObject
+	if data.Closure != "" {
+		label := widget.NewLabelWithStyle(data.Closure, fyne.TextAlignTrailing, fyne.TextStyle{Italic: true})
+		label.Importance = widget.WarningImportance
+		label.Truncation = fyne.TextTruncateEllipsis
+		closureLabel = label
+
+		// No paid hours for part-time and work-study on closures
+		if empType != models.TypeFullTime {
+			label.SetText("Closed: " + data.Closure)
+			cell.closed = true
+			cell.WorkedEntry.Disable()
+			if cell.SecondaryEntry != nil {
+				cell.SecondaryEntry.Disable()
+			}
+		}
+	}
+
 	var content *fyne.Container
 
 	// Full time inputs (accordion inputs for cleaner layout)
 	if empType == models.TypeFullTime {
-		cell.SickEntry = makeEntry(data.SickLeave, onChanged)
-		cell.VacationEntry = makeEntry(data.Vacation, onChanged)
-		cell.HolidayEntry = makeEntry(data.Holiday, onChanged)
-		cell.CompEntry = makeEntry(data.CompTimeTaken, onChanged)
-		cell.OtherEntry = makeEntry(data.OtherPaid, onChanged)
+		cell.SickEntry = makeEntry(data.SickLeave, changed)
+		cell.VacationEntry = makeEntry(data.Vacation, changed)
+		cell.HolidayEntry = makeEntry(data.Holiday, changed)
+		cell.CompEntry = makeEntry(data.CompTimeTaken, changed)
+		cell.OtherEntry = makeEntry(data.OtherPaid, changed)
 
 		//Create extras container with grid for alignment
 		cell.ExtrasContainer = container.NewVBox(
@@ -69,7 +126,7 @@ func NewDayCell(dayNum int, data models.DailyEntry, empType models.EmployeeType,
 			container.NewBorder(
 				nil, nil,
 				dayLabel,
-				nil, nil,
+				nil, closureLabel,
 			),
 			container.NewBorder(
 				nil, nil,
@@ -77,18 +134,32 @@ func NewDayCell(dayNum int, data models.DailyEntry, empType models.EmployeeType,
 				nil,
 				cell.WorkedEntry,
 			),
+			cell.PunchLabel,
 			cell.ExtrasContainer,
 		)
 	} else {
 		// Part tume and work study layout
 		content = container.NewVBox(
-			dayLabel,
+			container.NewBorder(nil, nil, dayLabel, nil, closureLabel),
 			container.NewBorder(nil, nil, widget.NewLabel("Hours:"), nil, cell.WorkedEntry),
 		)
+		// Of those hours, how many go to the secondary FOAP
+		if cell.SecondaryEntry != nil {
+			content.Add(container.NewBorder(nil, nil, widget.NewLabel("2nd:"), nil, cell.SecondaryEntry))
+		}
+		content.Add(cell.PunchLabel)
+	}
+
+	// Highlight bad input as it is typed
+	for _, e := range cell.entries() {
+		e.Validator = cell.checkField
+		e.AlwaysShowValidationError = true
 	}
 
 	card := widget.NewCard("", "", content)
 	cell.CanvasObj = card
+	cell.saved = cell.GetData()
+	cell.undoBase = cell.saved
 
 	return cell
 }
@@ -108,10 +179,10 @@ func inputRowGrid(label string, entry *widget.Entry) fyne.CanvasObject {
 // Create new day entry with data provided for cell
 func makeEntry(val float64, onChanged func()) *widget.Entry {
 	entry := widget.NewEntry()
-	entry.SetPlaceHolder("0.0")
+	entry.SetPlaceHolder("0")
 	//Cell not empty
 	if val > 0 {
-		entry.SetText(fmt.Sprintf("%.1f", val))
+		entry.SetText(formatEntryHours(val))
 	}
 
 	//Hook onChange to Fyne's event listener
@@ -131,6 +202,13 @@ func (day *DayCell) GetData() models.DailyEntry {
 	entry := models.DailyEntry{
 		Date:        day.DateStr,
 		HoursWorked: parseFloat(day.WorkedEntry.Text),
+		Closure:     day.Closure,
+		Punches:     day.Punches,
+
+		OvernightHours: day.Overnight,
+	}
+	if day.SecondaryEntry != nil {
+		entry.SecondaryHours = parseFloat(day.SecondaryEntry.Text)
 	}
 
 	// Verify full time data is filled based on sick leave
@@ -145,6 +223,88 @@ func (day *DayCell) GetData() models.DailyEntry {
 	return entry
 }
 
+// SetData fills the inputs from an entry, e.g. a recovered draft. Closure and date stay
+func (d *DayCell) SetData(e models.DailyEntry) {
+	d.Overnight = e.OvernightHours
+	d.setPunchLabel(e.Punches)
+
+	// Inputs the profile type does not show are nil and skipped
+	fields := []struct {
+		entry *widget.Entry
+		hours float64
+	}{
+		{d.WorkedEntry, e.HoursWorked},
+		{d.SecondaryEntry, e.SecondaryHours},
+		{d.SickEntry, e.SickLeave},
+		{d.VacationEntry, e.Vacation},
+		{d.HolidayEntry, e.Holiday},
+		{d.CompEntry, e.CompTimeTaken},
+		{d.OtherEntry, e.OtherPaid},
+	}
+	for _, f := range fields {
+		if f.entry == nil {
+			continue
+		}
+		if text := formatEntryHours(f.hours); text != f.entry.Text {
+			f.entry.SetText(text)
+		}
+	}
+}
+
+// Dirty reports whether the day differs from what was last loaded or saved
+func (d *DayCell) Dirty() bool {
+	return !models.SameEntry(d.saved, d.GetData())
+}
+
+// MarkSaved makes the current inputs the saved state
+func (d *DayCell) MarkSaved() {
+	d.saved = d.GetData()
+}
+
+// SetPunches replaces the day's punches. Once no punch is open, hours worked
+// are derived from the ranges
+func (d *DayCell) SetPunches(punches []models.TimeRange) {
+	d.setPunchLabel(punches)
+
+	entry := models.DailyEntry{Punches: punches}
+	if entry.OpenPunch() < 0 {
+		d.Overnight = entry.PunchedOvernightHours()
+		d.setPunchLabel(punches)
+		d.WorkedEntry.SetText(formatEntryHours(validation.CurrentRounding().Round(entry.PunchedHours())))
+	}
+}
+
+// Helper to show the punch list and any time past midnight under the hours input
+func (d *DayCell) setPunchLabel(punches []models.TimeRange) {
+	d.Punches = punches
+
+	var parts []string
+	if len(punches) > 0 {
+		parts = append(parts, models.DailyEntry{Punches: punches}.PunchString())
+	}
+	if d.Overnight > 0 {
+		parts = append(parts, fmt.Sprintf("%s hrs after midnight", formatEntryHours(d.Overnight)))
+	}
+
+	if len(parts) == 0 {
+		d.PunchLabel.Hide()
+		return
+	}
+	d.PunchLabel.SetText(strings.Join(parts, ", "))
+	d.PunchLabel.Show()
+}
+
+// SetLocked disables every input while the sheet is submitted or approved
+func (d *DayCell) SetLocked(locked bool) {
+	for _, e := range d.entries() {
+		if locked || (d.closed && (e == d.WorkedEntry || e == d.SecondaryEntry)) {
+			e.Disable()
+		} else {
+			e.Enable()
+		}
+	}
+}
+
 // Helper function to toggle extra fields in full time timesheet
 func (d *DayCell) SetExtrasVisible(show bool) {
 	if d.ExtrasContainer == nil {
@@ -157,11 +317,62 @@ func (d *DayCell) SetExtrasVisible(show bool) {
 	}
 }
 
-// Helper to convert hours worked from text to float
-func parseFloat(str string) float64 {
-	if str == "" {
-		return 0
+// Validate re-checks every input of the day, highlighting the bad ones, and
+// returns the first problem
+func (d *DayCell) Validate() error {
+	var first error
+	for _, e := range d.entries() {
+		if err := e.Validate(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// ApplyRounding rewrites the inputs with their rounded values, so the cell shows
+// what is saved. Invalid inputs are left for the user to fix
+func (d *DayCell) ApplyRounding() {
+	for _, e := range d.entries() {
+		hours, err := validation.ParseHours(e.Text)
+		if err != nil || e.Text == "" {
+			continue
+		}
+		if text := formatEntryHours(hours); text != e.Text {
+			e.SetText(text)
+		}
 	}
-	f, _ := strconv.ParseFloat(str, 64)
-	return f
+}
+
+// Validator for one input: its own text first, then the day's totals
+func (d *DayCell) checkField(text string) error {
+	if err := validation.CheckHours(text); err != nil {
+		return err
+	}
+	return validation.ValidateDay(d.GetData())
+}
+
+// Helper listing the cell's hour inputs, worked hours first
+func (d *DayCell) entries() []*widget.Entry {
+	var list []*widget.Entry
+	for _, e := range []*widget.Entry{d.WorkedEntry, d.SecondaryEntry, d.SickEntry, d.VacationEntry, d.HolidayEntry, d.CompEntry, d.OtherEntry} {
+		if e != nil {
+			list = append(list, e)
+		}
+	}
+	return list
+}
+
+// Helper to show hours without trailing zeros (7.25, 8)
+func formatEntryHours(hours float64) string {
+	if hours == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(hours, 'f', -1, 64)
+}
+
+// Helper to convert hours from text to float, rounded with the active rule.
+// Invalid text reads as 0; Validate reports it
+func parseFloat(str string) float64 {
+	hours, _ := validation.ParseHours(str)
+	return hours
 }
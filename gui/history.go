@@ -0,0 +1,121 @@
+package gui
+
+import (
+	"fmt"
+	"time"
+
+	"calendar_utility_node_for_timesheets/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showHistory compares any two saved revisions of the period on screen and can restore an older one
+func (c *CalendarPage) showHistory() {
+	if c.Profile == nil {
+		return
+	}
+
+	revisions, err := c.Repo.GetTimesheetRevisions(c.Profile.ID, c.Period)
+	if err != nil {
+		dialog.ShowError(err, c.Window)
+		return
+	}
+	if len(revisions) == 0 {
+		dialog.ShowInformation("History", "This timesheet has not been saved yet.", c.Window)
+		return
+	}
+
+	// Options read "#12  Oct 17 14:02  Saved", newest first
+	options := make([]string, len(revisions))
+	for i, rev := range revisions {
+		options[i] = fmt.Sprintf("#%d  %s  %s", rev.ID, rev.SavedAt.Local().Format("Jan 2 15:04"), rev.Note)
+	}
+
+	diffBox := container.NewVBox()
+	fromSelect := widget.NewSelect(options, nil)
+	toSelect := widget.NewSelect(options, nil)
+
+	// Redraw the day-by-day differences between the two picks
+	showDiff := func() {
+		diffBox.Objects = nil
+		from, to := fromSelect.SelectedIndex(), toSelect.SelectedIndex()
+		if from < 0 || to < 0 {
+			diffBox.Refresh()
+			return
+		}
+
+		changes := models.DiffEntries(revisions[from].Entries, revisions[to].Entries)
+		if len(changes) == 0 {
+			diffBox.Add(widget.NewLabel("No differences in daily entries."))
+		}
+		for _, change := range changes {
+			date := change.Date
+			if d, err := time.Parse("2006-01-02", change.Date); err == nil {
+				date = d.Format("Mon Jan 2")
+			}
+			row := widget.NewLabel(fmt.Sprintf("%s: %s", date, change.Describe()))
+			row.Wrapping = fyne.TextWrapWord
+			diffBox.Add(row)
+		}
+		diffBox.Refresh()
+	}
+	fromSelect.OnChanged = func(string) { showDiff() }
+	toSelect.OnChanged = func(string) { showDiff() }
+
+	// Default to the latest save against the one before it
+	toSelect.SetSelectedIndex(0)
+	if len(revisions) > 1 {
+		fromSelect.SetSelectedIndex(1)
+	} else {
+		fromSelect.SetSelectedIndex(0)
+	}
+
+	var d dialog.Dialog
+	restoreBtn := widget.NewButtonWithIcon("Restore \"From\" Revision", theme.HistoryIcon(), func() {
+		idx := fromSelect.SelectedIndex()
+		if idx < 0 {
+			return
+		}
+		rev := revisions[idx]
+
+		msg := fmt.Sprintf("Replace this timesheet's entries with revision #%d? The current version stays in the history.", rev.ID)
+		dialog.ShowConfirm("Restore Revision", msg, func(ok bool) {
+			if !ok {
+				return
+			}
+			d.Hide()
+
+			// Unsaved edits are saved or dropped first, so the restore does not lose them silently
+			c.ConfirmLeave(func() {
+				if err := c.storeEntries(rev.Entries, fmt.Sprintf("Restored revision #%d", rev.ID)); err != nil {
+					dialog.ShowError(err, c.Window)
+					return
+				}
+				c.Refresh()
+			})
+		}, c.Window)
+	})
+	if c.locked() {
+		restoreBtn.Disable()
+	}
+
+	form := widget.NewForm(
+		widget.NewFormItem("From", fromSelect),
+		widget.NewFormItem("To", toSelect),
+	)
+	content := container.NewBorder(
+		form,
+		restoreBtn,
+		nil, nil,
+		container.NewVScroll(diffBox),
+	)
+
+	title := fmt.Sprintf("History - %s", c.Period.Label())
+	d = dialog.NewCustom(title, "Close", content, c.Window)
+	d.Resize(fyne.NewSize(640, 480))
+	d.Show()
+}
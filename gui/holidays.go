@@ -0,0 +1,147 @@
+package gui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"calendar_utility_node_for_timesheets/db"
+	"calendar_utility_node_for_timesheets/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showHolidaysDialog lists the holiday calendar and lets the user add, delete or import days
+func showHolidaysDialog(win fyne.Window, repo *db.Repository, onChanged func()) {
+	var holidays []models.Holiday
+	selected := -1
+
+	list := widget.NewList(
+		func() int { return len(holidays) },
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, widget.NewLabel("2006-01-02 Mon"), nil, widget.NewLabel(""))
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			h := holidays[id]
+			row := obj.(*fyne.Container)
+			date := h.Date
+			if d, err := time.Parse("2006-01-02", h.Date); err == nil {
+				date = d.Format("2006-01-02 Mon")
+			}
+			row.Objects[1].(*widget.Label).SetText(date)
+			row.Objects[0].(*widget.Label).SetText(h.Name)
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) { selected = id }
+	list.OnUnselected = func(id widget.ListItemID) { selected = -1 }
+
+	reload := func() {
+		var err error
+		holidays, err = repo.GetHolidays()
+		if err != nil {
+			dialog.ShowError(err, win)
+		}
+		selected = -1
+		list.UnselectAll()
+		list.Refresh()
+	}
+	changed := func() {
+		reload()
+		if onChanged != nil {
+			onChanged()
+		}
+	}
+
+	addBtn := widget.NewButtonWithIcon("Add", theme.ContentAddIcon(), func() {
+		dateEntry := widget.NewEntry()
+		dateEntry.SetPlaceHolder("YYYY-MM-DD")
+		nameEntry := widget.NewEntry()
+		nameEntry.SetPlaceHolder("Thanksgiving")
+
+		dialog.ShowForm("Add Holiday", "Add", "Cancel", []*widget.FormItem{
+			widget.NewFormItem("Date", dateEntry),
+			widget.NewFormItem("Name", nameEntry),
+		}, func(ok bool) {
+			if !ok {
+				return
+			}
+			h := models.Holiday{Date: strings.TrimSpace(dateEntry.Text), Name: strings.TrimSpace(nameEntry.Text)}
+			if _, err := time.Parse("2006-01-02", h.Date); err != nil {
+				dialog.ShowError(fmt.Errorf("date %q is not YYYY-MM-DD", h.Date), win)
+				return
+			}
+			if h.Name == "" {
+				h.Name = "Closed"
+			}
+			if err := repo.SaveHolidays([]models.Holiday{h}); err != nil {
+				dialog.ShowError(err, win)
+				return
+			}
+			changed()
+		}, win)
+	})
+
+	deleteBtn := widget.NewButtonWithIcon("Delete", theme.DeleteIcon(), func() {
+		if selected < 0 || selected >= len(holidays) {
+			return
+		}
+		if err := repo.DeleteHoliday(holidays[selected].Date); err != nil {
+			dialog.ShowError(err, win)
+			return
+		}
+		changed()
+	})
+
+	importBtn := widget.NewButtonWithIcon("Import", theme.FolderOpenIcon(), func() {
+		openDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, win)
+				return
+			}
+			if reader == nil {
+				return // User cancelled
+			}
+			defer reader.Close()
+
+			data, err := io.ReadAll(reader)
+			if err != nil {
+				dialog.ShowError(err, win)
+				return
+			}
+
+			parsed, err := models.ParseHolidays(data)
+			if err != nil {
+				dialog.ShowError(err, win)
+				return
+			}
+			if err := repo.SaveHolidays(parsed); err != nil {
+				dialog.ShowError(err, win)
+				return
+			}
+			changed()
+			dialog.ShowInformation("Imported", fmt.Sprintf("Imported %d holidays.", len(parsed)), win)
+		}, win)
+		openDialog.SetFilter(storage.NewExtensionFileFilter([]string{".csv", ".json", ".txt"}))
+		openDialog.Show()
+	})
+
+	reload()
+
+	help := widget.NewLabel("Full-time staff get their scheduled hours as Holiday on these days.\nPart-time and work-study days are marked closed and zeroed.")
+	content := container.NewBorder(
+		help,
+		container.NewHBox(addBtn, deleteBtn, importBtn),
+		nil, nil,
+		list,
+	)
+
+	d := dialog.NewCustom("Holidays & Closures", "Close", content, win)
+	d.Resize(fyne.NewSize(460, 480))
+	d.Show()
+}
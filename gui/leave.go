@@ -0,0 +1,236 @@
+package gui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"calendar_utility_node_for_timesheets/db"
+	"calendar_utility_node_for_timesheets/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Display names for leave types
+var leaveTypeLabels = map[models.LeaveType]string{
+	models.LeaveSick:     "Sick Leave",
+	models.LeaveVacation: "Vacation",
+	models.LeaveComp:     "Comp Time",
+}
+
+// LeavePage shows the full-time leave balances and their monthly ledger
+type LeavePage struct {
+	Repo   *db.Repository
+	Window fyne.Window
+
+	Profile *models.Profile
+
+	// UI components
+	EditBtn *widget.Button
+	Content *fyne.Container
+}
+
+func NewLeavePage(win fyne.Window, repo *db.Repository) *LeavePage {
+	l := &LeavePage{
+		Repo:   repo,
+		Window: win,
+	}
+
+	l.Content = container.NewVBox()
+	l.EditBtn = widget.NewButtonWithIcon("Edit Balances", theme.DocumentCreateIcon(), l.editAccounts)
+
+	return l
+}
+
+func (l *LeavePage) BuildUI() fyne.CanvasObject {
+	header := container.NewHBox(
+		widget.NewLabelWithStyle("Leave Balances", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		layoutSpacer(0),
+		l.EditBtn,
+	)
+
+	l.Refresh()
+
+	return container.NewBorder(header, nil, nil, nil, container.NewScroll(l.Content))
+}
+
+// Refresh reloads the active profile's balances and ledger
+func (l *LeavePage) Refresh() {
+	l.Content.Objects = nil
+	l.EditBtn.Disable()
+
+	prof, err := l.Repo.GetProfile()
+	if err != nil {
+		dialog.ShowError(err, l.Window)
+		return
+	}
+	l.Profile = prof
+
+	if prof == nil || prof.Type != models.TypeFullTime {
+		l.Content.Add(widget.NewLabel("Leave balances are tracked for full-time profiles."))
+		l.Content.Refresh()
+		return
+	}
+	l.EditBtn.Enable()
+
+	accounts, err := l.Repo.GetLeaveAccounts(prof.ID)
+	if err != nil {
+		dialog.ShowError(err, l.Window)
+		return
+	}
+	if len(accounts) == 0 {
+		l.Content.Add(widget.NewLabel("No starting balances yet. Use Edit Balances to set them up."))
+		l.Content.Refresh()
+		return
+	}
+
+	now := time.Now()
+	for _, t := range models.LeaveTypes {
+		account, ok := accounts[t]
+		if !ok {
+			continue
+		}
+		ledger, err := l.Repo.GetLeaveLedger(prof.ID, t, int(now.Month()), now.Year())
+		if err != nil {
+			dialog.ShowError(err, l.Window)
+			return
+		}
+		l.Content.Add(l.buildLedgerCard(account, ledger))
+	}
+	l.Content.Refresh()
+}
+
+// One card per leave type, current balance on top and the monthly ledger below
+func (l *LeavePage) buildLedgerCard(account models.LeaveAccount, ledger []models.LeaveLedgerLine) fyne.CanvasObject {
+	balance := account.StartingBalance
+	if len(ledger) > 0 {
+		balance = ledger[len(ledger)-1].Balance
+	}
+
+	balanceLabel := widget.NewLabelWithStyle(fmt.Sprintf("Balance: %.2f hrs", balance), fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	if balance < 0 {
+		balanceLabel.Importance = widget.DangerImportance
+	}
+	subtitle := fmt.Sprintf("Starting %.2f hrs in %s, accrues %.2f hrs/month",
+		account.StartingBalance,
+		time.Date(account.StartYear, time.Month(account.StartMonth), 1, 0, 0, 0, 0, time.Local).Format("Jan 2006"),
+		account.AccrualRate)
+
+	headers := []string{"Month", "Opening", "Accrued", "Earned", "Used", "Balance"}
+	rows := container.NewGridWithColumns(len(headers))
+	for _, h := range headers {
+		rows.Add(widget.NewLabelWithStyle(h, fyne.TextAlignCenter, fyne.TextStyle{Bold: true}))
+	}
+
+	// Newest month first
+	for i := len(ledger) - 1; i >= 0; i-- {
+		line := ledger[i]
+		balanceCell := widget.NewLabelWithStyle(fmt.Sprintf("%.2f", line.Balance), fyne.TextAlignCenter, fyne.TextStyle{})
+		if line.Balance < 0 {
+			balanceCell.Importance = widget.DangerImportance
+		}
+		rows.Add(widget.NewLabel(time.Date(line.Year, time.Month(line.Month), 1, 0, 0, 0, 0, time.Local).Format("Jan 2006")))
+		rows.Add(widget.NewLabelWithStyle(fmt.Sprintf("%.2f", line.Opening), fyne.TextAlignCenter, fyne.TextStyle{}))
+		rows.Add(widget.NewLabelWithStyle(formatHours(line.Accrued), fyne.TextAlignCenter, fyne.TextStyle{}))
+		rows.Add(widget.NewLabelWithStyle(formatHours(line.Earned), fyne.TextAlignCenter, fyne.TextStyle{}))
+		rows.Add(widget.NewLabelWithStyle(formatHours(line.Used), fyne.TextAlignCenter, fyne.TextStyle{}))
+		rows.Add(balanceCell)
+	}
+
+	return widget.NewCard(leaveTypeLabels[account.Type], subtitle, container.NewVBox(balanceLabel, widget.NewSeparator(), rows))
+}
+
+// Form for the starting balance and accrual of each leave type
+func (l *LeavePage) editAccounts() {
+	if l.Profile == nil {
+		return
+	}
+
+	accounts, err := l.Repo.GetLeaveAccounts(l.Profile.ID)
+	if err != nil {
+		dialog.ShowError(err, l.Window)
+		return
+	}
+
+	now := time.Now()
+	startEntries := make(map[models.LeaveType]*widget.Entry)
+	accrualEntries := make(map[models.LeaveType]*widget.Entry)
+	sinceEntries := make(map[models.LeaveType]*widget.Entry)
+
+	var items []*widget.FormItem
+	for _, t := range models.LeaveTypes {
+		account, ok := accounts[t]
+		if !ok {
+			account = models.LeaveAccount{StartMonth: int(now.Month()), StartYear: now.Year()}
+		}
+
+		start := widget.NewEntry()
+		start.SetText(strconv.FormatFloat(account.StartingBalance, 'f', -1, 64))
+		accrual := widget.NewEntry()
+		accrual.SetText(strconv.FormatFloat(account.AccrualRate, 'f', -1, 64))
+		since := widget.NewEntry()
+		since.SetPlaceHolder("YYYY-MM")
+		since.SetText(fmt.Sprintf("%04d-%02d", account.StartYear, account.StartMonth))
+
+		startEntries[t], accrualEntries[t], sinceEntries[t] = start, accrual, since
+
+		label := leaveTypeLabels[t]
+		items = append(items,
+			widget.NewFormItem(label+" Starting", start),
+			widget.NewFormItem(label+" Accrual/Month", accrual),
+			widget.NewFormItem(label+" As Of", since),
+		)
+	}
+
+	dialog.ShowForm("Leave Balances", "Save", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		for _, t := range models.LeaveTypes {
+			label := leaveTypeLabels[t]
+			start, err := strconv.ParseFloat(strings.TrimSpace(startEntries[t].Text), 64)
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("%s starting balance must be a number", label), l.Window)
+				return
+			}
+			accrual, err := strconv.ParseFloat(strings.TrimSpace(accrualEntries[t].Text), 64)
+			if err != nil || accrual < 0 {
+				dialog.ShowError(fmt.Errorf("%s accrual must be a non-negative number", label), l.Window)
+				return
+			}
+			since, err := time.Parse("2006-01", strings.TrimSpace(sinceEntries[t].Text))
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("%s start month must be YYYY-MM", label), l.Window)
+				return
+			}
+
+			account := models.LeaveAccount{
+				ProfileID:       l.Profile.ID,
+				Type:            t,
+				StartingBalance: start,
+				AccrualRate:     accrual,
+				StartMonth:      int(since.Month()),
+				StartYear:       since.Year(),
+			}
+			if err := l.Repo.SaveLeaveAccount(account); err != nil {
+				dialog.ShowError(err, l.Window)
+				return
+			}
+		}
+		l.Refresh()
+	}, l.Window)
+}
+
+// Helper to show blank instead of 0.00 in the ledger
+func formatHours(hours float64) string {
+	if hours == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.2f", hours)
+}
@@ -0,0 +1,113 @@
+package gui
+
+import (
+	"fmt"
+	"strconv"
+
+	"calendar_utility_node_for_timesheets/db"
+	"calendar_utility_node_for_timesheets/models"
+	"calendar_utility_node_for_timesheets/rules"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Labels for the hour types a policy can count toward overtime
+var hourTypeLabels = map[rules.HourType]string{
+	rules.Worked:    "Hours worked",
+	rules.Sick:      "Sick leave",
+	rules.Vacation:  "Vacation",
+	rules.Holiday:   "Holiday",
+	rules.CompTaken: "Comp time taken",
+	rules.OtherPaid: "Other paid",
+}
+
+// Labels for the night shift rules, in display order
+var overnightRules = []rules.OvernightRule{rules.OvernightStartDay, rules.OvernightSplit}
+var overnightRuleLabels = map[rules.OvernightRule]string{
+	rules.OvernightStartDay: "Count on the day the shift starts",
+	rules.OvernightSplit:    "Split at midnight",
+}
+
+// showOvertimeRulesDialog edits the overtime policy for one employee type
+func showOvertimeRulesDialog(win fyne.Window, repo *db.Repository, empType models.EmployeeType, onSaved func()) {
+	policy := rules.PolicyFor(empType)
+
+	threshold := widget.NewEntry()
+	threshold.SetText(strconv.FormatFloat(policy.WeeklyThreshold, 'f', -1, 64))
+	compRate := widget.NewEntry()
+	compRate.SetText(strconv.FormatFloat(policy.CompTimeRate, 'f', -1, 64))
+	bankComp := widget.NewCheck("Bank overtime as comp time", nil)
+	bankComp.SetChecked(policy.BankCompTime)
+	straightTime := widget.NewCheck("Pay overtime at the regular rate", nil)
+	straightTime.SetChecked(policy.StraightTime)
+
+	overnightOptions := make([]string, len(overnightRules))
+	for i, rule := range overnightRules {
+		overnightOptions[i] = overnightRuleLabels[rule]
+	}
+	overnight := widget.NewSelect(overnightOptions, nil)
+	overnight.SetSelectedIndex(0)
+	if policy.Overnight == rules.OvernightSplit {
+		overnight.SetSelectedIndex(1)
+	}
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Weekly Threshold", threshold),
+		widget.NewFormItem("Comp Time Rate", compRate),
+		widget.NewFormItem("", bankComp),
+		widget.NewFormItem("", straightTime),
+		widget.NewFormItem("Night Shifts", overnight),
+	}
+
+	// One checkbox per hour type that may count toward the threshold
+	counts := make(map[rules.HourType]*widget.Check)
+	for i, ht := range rules.HourTypes {
+		check := widget.NewCheck(hourTypeLabels[ht], nil)
+		check.SetChecked(policy.Counts[ht])
+		counts[ht] = check
+
+		label := ""
+		if i == 0 {
+			label = "Counts Toward OT"
+		}
+		items = append(items, widget.NewFormItem(label, check))
+	}
+
+	title := fmt.Sprintf("%s Overtime Rules", empType)
+	dialog.ShowForm(title, "Save", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		var updated rules.Policy
+		var err error
+		if updated.WeeklyThreshold, err = strconv.ParseFloat(threshold.Text, 64); err != nil || updated.WeeklyThreshold < 0 {
+			dialog.ShowError(fmt.Errorf("weekly threshold must be a positive number"), win)
+			return
+		}
+		if updated.CompTimeRate, err = strconv.ParseFloat(compRate.Text, 64); err != nil || updated.CompTimeRate < 0 {
+			dialog.ShowError(fmt.Errorf("comp time rate must be a positive number"), win)
+			return
+		}
+		updated.BankCompTime = bankComp.Checked
+		updated.StraightTime = straightTime.Checked
+		if idx := overnight.SelectedIndex(); idx >= 0 {
+			updated.Overnight = overnightRules[idx]
+		}
+		updated.Counts = make(map[rules.HourType]bool)
+		for ht, check := range counts {
+			updated.Counts[ht] = check.Checked
+		}
+
+		if err := repo.SaveOvertimePolicy(empType, updated); err != nil {
+			dialog.ShowError(err, win)
+			return
+		}
+
+		if onSaved != nil {
+			onSaved()
+		}
+	}, win)
+}
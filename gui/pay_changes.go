@@ -0,0 +1,144 @@
+package gui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"calendar_utility_node_for_timesheets/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showPayChangesDialog edits the effective-dated rate and FOAP changes of a profile.
+// onDone receives the new list, it is saved with the rest of the profile.
+func showPayChangesDialog(win fyne.Window, current []models.PayTerms, onDone func([]models.PayTerms)) {
+	changes := append([]models.PayTerms(nil), current...)
+	selected := -1
+
+	list := widget.NewList(
+		func() int { return len(changes) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			obj.(*widget.Label).SetText(payTermsSummary(changes[id]))
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) { selected = id }
+	list.OnUnselected = func(id widget.ListItemID) { selected = -1 }
+
+	addBtn := widget.NewButtonWithIcon("Add Change", theme.ContentAddIcon(), func() {
+		showPayTermsForm(win, func(t models.PayTerms) {
+			changes = append(changes, t)
+			sort.SliceStable(changes, func(i, j int) bool { return changes[i].EffectiveDate < changes[j].EffectiveDate })
+			list.UnselectAll()
+			list.Refresh()
+		})
+	})
+	deleteBtn := widget.NewButtonWithIcon("Delete", theme.DeleteIcon(), func() {
+		if selected < 0 || selected >= len(changes) {
+			return
+		}
+		changes = append(changes[:selected], changes[selected+1:]...)
+		list.UnselectAll()
+		list.Refresh()
+	})
+
+	help := widget.NewLabel("The profile's own rate and codes apply until the first change.\nSave the profile to keep these changes.")
+	content := container.NewBorder(help, container.NewHBox(addBtn, deleteBtn), nil, nil, list)
+
+	d := dialog.NewCustomConfirm("Rate & FOAP Changes", "Done", "Cancel", content, func(ok bool) {
+		if ok {
+			onDone(changes)
+		}
+	}, win)
+	d.Resize(fyne.NewSize(560, 420))
+	d.Show()
+}
+
+// Form for one dated change
+func showPayTermsForm(win fyne.Window, onAdd func(models.PayTerms)) {
+	date := widget.NewEntry()
+	date.SetPlaceHolder("YYYY-MM-DD")
+	rate := widget.NewEntry()
+	fund, org, acct, prog := widget.NewEntry(), widget.NewEntry(), widget.NewEntry(), widget.NewEntry()
+	fund2, org2, acct2, prog2 := widget.NewEntry(), widget.NewEntry(), widget.NewEntry(), widget.NewEntry()
+	rate2 := widget.NewEntry()
+	rate2.SetPlaceHolder("Optional")
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Effective Date", date),
+		widget.NewFormItem("Hourly Rate", rate),
+		widget.NewFormItem("Fund", fund),
+		widget.NewFormItem("Org", org),
+		widget.NewFormItem("Account", acct),
+		widget.NewFormItem("Program", prog),
+		widget.NewFormItem("Secondary Fund", fund2),
+		widget.NewFormItem("Secondary Org", org2),
+		widget.NewFormItem("Secondary Account", acct2),
+		widget.NewFormItem("Secondary Program", prog2),
+		widget.NewFormItem("Secondary Rate", rate2),
+	}
+
+	dialog.ShowForm("Add Rate/FOAP Change", "Add", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		eff := strings.TrimSpace(date.Text)
+		if _, err := time.Parse("2006-01-02", eff); err != nil {
+			dialog.ShowError(fmt.Errorf("effective date %q is not YYYY-MM-DD", eff), win)
+			return
+		}
+		r, err := strconv.ParseFloat(strings.TrimSpace(rate.Text), 64)
+		if err != nil || r < 0 {
+			dialog.ShowError(fmt.Errorf("hourly rate must be a non-negative number"), win)
+			return
+		}
+
+		terms := models.PayTerms{
+			EffectiveDate: eff,
+			Rate:          r,
+			PrimaryAccounting: models.AccountingCodes{
+				Fund:         fund.Text,
+				Organization: org.Text,
+				Account:      acct.Text,
+				Program:      prog.Text,
+			},
+		}
+		if fund2.Text != "" || org2.Text != "" || acct2.Text != "" || prog2.Text != "" {
+			terms.SecondaryAccounting = &models.AccountingCodes{
+				Fund:         fund2.Text,
+				Organization: org2.Text,
+				Account:      acct2.Text,
+				Program:      prog2.Text,
+			}
+			if text := strings.TrimSpace(rate2.Text); text != "" {
+				r2, err := strconv.ParseFloat(text, 64)
+				if err != nil || r2 < 0 {
+					dialog.ShowError(fmt.Errorf("secondary rate must be a non-negative number"), win)
+					return
+				}
+				terms.SecondaryAccounting.HourlyRate = r2
+			}
+		}
+		onAdd(terms)
+	}, win)
+}
+
+// One-line description of a change for lists
+func payTermsSummary(t models.PayTerms) string {
+	codes := t.PrimaryAccounting
+	summary := fmt.Sprintf("From %s: $%.2f/hr  %s-%s-%s-%s", t.EffectiveDate, t.Rate,
+		codes.Fund, codes.Organization, codes.Account, codes.Program)
+	if t.SecondaryAccounting != nil {
+		c2 := t.SecondaryAccounting
+		summary += fmt.Sprintf("  (2nd %s-%s-%s-%s)", c2.Fund, c2.Organization, c2.Account, c2.Program)
+	}
+	return summary
+}
@@ -0,0 +1,116 @@
+package gui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"calendar_utility_node_for_timesheets/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Pay period kinds in menu order, with their labels
+var periodKinds = []models.PeriodKind{models.PeriodMonthly, models.PeriodFixed, models.PeriodSemiMonthly}
+
+var periodKindLabels = map[models.PeriodKind]string{
+	models.PeriodMonthly:     "Calendar month",
+	models.PeriodFixed:       "Fixed length (biweekly, weekly...)",
+	models.PeriodSemiMonthly: "Semi-monthly",
+}
+
+// showPayPeriodDialog edits the profile's pay period definition. Sheets already saved
+// keep the period they were saved with
+func showPayPeriodDialog(win fyne.Window, current models.PayPeriod, onDone func(models.PayPeriod)) {
+	options := make([]string, len(periodKinds))
+	for i, kind := range periodKinds {
+		options[i] = periodKindLabels[kind]
+	}
+
+	anchor := widget.NewEntry()
+	anchor.SetPlaceHolder("YYYY-MM-DD, first day of any period")
+	anchor.SetText(current.Anchor)
+	length := widget.NewEntry()
+	length.SetPlaceHolder("14")
+	if current.LengthDays > 0 {
+		length.SetText(strconv.Itoa(current.LengthDays))
+	}
+	split := widget.NewEntry()
+	split.SetPlaceHolder("15")
+	if current.SplitDay > 0 {
+		split.SetText(strconv.Itoa(current.SplitDay))
+	}
+
+	kindSelect := widget.NewSelect(options, nil)
+	selectedKind := func() models.PeriodKind {
+		if idx := kindSelect.SelectedIndex(); idx >= 0 {
+			return periodKinds[idx]
+		}
+		return models.PeriodMonthly
+	}
+
+	// Only the fields of the chosen kind can be edited
+	kindSelect.OnChanged = func(string) {
+		anchor.Disable()
+		length.Disable()
+		split.Disable()
+		switch selectedKind() {
+		case models.PeriodFixed:
+			anchor.Enable()
+			length.Enable()
+		case models.PeriodSemiMonthly:
+			split.Enable()
+		}
+	}
+	kindSelect.SetSelected(periodKindLabels[current.Kind])
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Period", kindSelect),
+		widget.NewFormItem("Starting", anchor),
+		widget.NewFormItem("Length (days)", length),
+		widget.NewFormItem("First Half Ends", split),
+	}
+
+	dialog.ShowForm("Pay Period", "Done", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		pp := models.PayPeriod{Kind: selectedKind()}
+		switch pp.Kind {
+		case models.PeriodFixed:
+			pp.Anchor = strings.TrimSpace(anchor.Text)
+			days, err := strconv.Atoi(strings.TrimSpace(length.Text))
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("length must be a whole number of days"), win)
+				return
+			}
+			pp.LengthDays = days
+		case models.PeriodSemiMonthly:
+			if text := strings.TrimSpace(split.Text); text != "" {
+				day, err := strconv.Atoi(text)
+				if err != nil {
+					dialog.ShowError(fmt.Errorf("first half must end on a day of the month"), win)
+					return
+				}
+				pp.SplitDay = day
+			}
+		}
+		if err := pp.Validate(); err != nil {
+			dialog.ShowError(err, win)
+			return
+		}
+		onDone(pp)
+	}, win)
+}
+
+// One-line description of a definition with the period holding today
+func payPeriodSummary(pp models.PayPeriod) string {
+	if pp.Kind == models.PeriodMonthly {
+		return "Monthly timesheets"
+	}
+	return fmt.Sprintf("%s (current: %s)", pp.Describe(), pp.PeriodFor(time.Now()).Label())
+}
@@ -0,0 +1,160 @@
+package gui
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"os"
+
+	"calendar_utility_node_for_timesheets/pdfgen/preview"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Resolution pages are rendered at. 100% zoom shows them at this size
+const previewDPI = 96
+
+// Zoom steps in percent, previewZoomDefault is the starting one
+var previewZooms = []int{50, 75, 100, 125, 150, 200}
+
+const previewZoomDefault = 2
+
+// showPDFPreview shows a generated PDF page by page before it is saved. Export asks where
+// to save it; either way the file at pdfPath is removed when the dialog closes
+func showPDFPreview(win fyne.Window, title, pdfPath, defaultName string) {
+	// Without pages the dialog explains why, the file can still be exported
+	pages, err := preview.RenderPages(pdfPath, previewDPI)
+
+	page, zoom := 0, previewZoomDefault
+	pageLabel := widget.NewLabel("")
+	zoomLabel := widget.NewLabel("")
+	view := container.NewStack()
+	scroll := container.NewScroll(container.NewCenter(view))
+
+	var prevBtn, nextBtn, zoomOutBtn, zoomInBtn *widget.Button
+	show := func() {
+		if len(pages) == 0 {
+			return
+		}
+		img := canvas.NewImageFromImage(pages[page])
+		img.FillMode = canvas.ImageFillContain
+		img.SetMinSize(pageSize(pages[page], previewZooms[zoom]))
+		view.Objects = []fyne.CanvasObject{img}
+		view.Refresh()
+		scroll.Refresh()
+
+		pageLabel.SetText(fmt.Sprintf("Page %d of %d", page+1, len(pages)))
+		zoomLabel.SetText(fmt.Sprintf("%d%%", previewZooms[zoom]))
+		setEnabled(prevBtn, page > 0)
+		setEnabled(nextBtn, page < len(pages)-1)
+		setEnabled(zoomOutBtn, zoom > 0)
+		setEnabled(zoomInBtn, zoom < len(previewZooms)-1)
+	}
+
+	prevBtn = widget.NewButtonWithIcon("", theme.NavigateBackIcon(), func() {
+		page--
+		show()
+	})
+	nextBtn = widget.NewButtonWithIcon("", theme.NavigateNextIcon(), func() {
+		page++
+		show()
+	})
+	zoomOutBtn = widget.NewButtonWithIcon("", theme.ZoomOutIcon(), func() {
+		zoom--
+		show()
+	})
+	zoomInBtn = widget.NewButtonWithIcon("", theme.ZoomInIcon(), func() {
+		zoom++
+		show()
+	})
+
+	var body fyne.CanvasObject = scroll
+	toolbar := container.NewHBox(prevBtn, pageLabel, nextBtn, layoutSpacer(0), zoomOutBtn, zoomLabel, zoomInBtn)
+	if len(pages) == 0 {
+		notice := widget.NewLabel("Preview unavailable: " + err.Error())
+		notice.Wrapping = fyne.TextWrapWord
+		body = container.NewCenter(notice)
+		toolbar.Hide()
+	}
+	show()
+
+	var d *dialog.CustomDialog
+	exporting := false
+	cleanup := func() {
+		os.Remove(pdfPath) // A leftover temp file is not worth an error
+	}
+	exportBtn := widget.NewButtonWithIcon("Export", theme.DocumentSaveIcon(), func() {
+		exporting = true
+		d.Hide()
+		savePDF(win, pdfPath, defaultName, cleanup)
+	})
+	exportBtn.Importance = widget.HighImportance
+	cancelBtn := widget.NewButtonWithIcon("Cancel", theme.CancelIcon(), func() {
+		d.Hide()
+	})
+
+	d = dialog.NewCustomWithoutButtons("Preview - "+title, container.NewBorder(toolbar, nil, nil, nil, body), win)
+	d.SetButtons([]fyne.CanvasObject{cancelBtn, exportBtn})
+	d.SetOnClosed(func() {
+		// Export removes the file once it is saved
+		if !exporting {
+			cleanup()
+		}
+	})
+	d.Resize(fyne.NewSize(760, 640))
+	d.Show()
+}
+
+// Ask where to save the previewed PDF and copy it there, then run done
+func savePDF(win fyne.Window, pdfPath, defaultName string, done func()) {
+	saveDialog := dialog.NewFileSave(func(uc fyne.URIWriteCloser, err error) {
+		defer done()
+		if err != nil {
+			dialog.ShowError(err, win)
+			return
+		}
+		if uc == nil {
+			return // User cancelled
+		}
+		defer uc.Close()
+
+		src, err := os.Open(pdfPath)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("failed to read PDF: %v", err), win)
+			return
+		}
+		defer src.Close()
+		if _, err := io.Copy(uc, src); err != nil {
+			dialog.ShowError(fmt.Errorf("failed to write PDF: %v", err), win)
+			return
+		}
+
+		dialog.ShowInformation("Success", "PDF exported successfully!", win)
+	}, win)
+
+	saveDialog.SetFileName(defaultName)
+	saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{".pdf"}))
+	saveDialog.Show()
+}
+
+// Helper sizing a rendered page for a zoom percentage
+func pageSize(img image.Image, zoom int) fyne.Size {
+	b := img.Bounds()
+	scale := float32(zoom) / 100
+	return fyne.NewSize(float32(b.Dx())*scale, float32(b.Dy())*scale)
+}
+
+// Helper to enable or disable a button
+func setEnabled(btn *widget.Button, on bool) {
+	if on {
+		btn.Enable()
+	} else {
+		btn.Disable()
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"calendar_utility_node_for_timesheets/db"
 	"calendar_utility_node_for_timesheets/models"
@@ -26,13 +27,15 @@ type ProfileExport struct {
 }
 
 type ProfilePage struct {
-	Repo   *db.Repository
-	Window fyne.Window
+	Repo    *db.Repository
+	Window  fyne.Window
+	History *UndoHistory // Shared with the Calendar tab
 
 	// Form widgets
 	FirstName   *widget.Entry
 	LastName    *widget.Entry
 	Middle      *widget.Entry
+	MiddleName  *widget.Entry
 	EmpID       *widget.Entry
 	PositionNum *widget.Entry
 	Dept        *widget.Entry
@@ -61,6 +64,22 @@ type ProfilePage struct {
 	Prog2          *widget.Entry
 	Rate2          *widget.Entry
 
+	// Effective-dated rate and FOAP changes, saved with the profile
+	PayChanges       []models.PayTerms
+	PayChangesLabel  *widget.Label
+	PayChangesButton *widget.Button
+
+	// Span of each timesheet, saved with the profile
+	PayPeriod       models.PayPeriod
+	PayPeriodLabel  *widget.Label
+	PayPeriodButton *widget.Button
+
+	// Work-study allocation fields
+	WorkStudyGroup  *fyne.Container
+	SemesterStart   *widget.Entry
+	Allocation      *widget.Entry
+	StartingBalance *widget.Entry
+
 	//Type selector
 	TypeSelect *widget.Select
 
@@ -68,22 +87,37 @@ type ProfilePage struct {
 	ScheduleInputs map[int]*widget.Entry
 
 	//State buttons
-	SaveButton   *widget.Button
-	EditButton   *widget.Button
-	ExportButton *widget.Button
-	ImportButton *widget.Button
+	SaveButton     *widget.Button
+	EditButton     *widget.Button
+	ExportButton   *widget.Button
+	ImportButton   *widget.Button
+	RulesButton    *widget.Button
+	RoundingButton *widget.Button
+
+	//Profile switcher (one profile per job)
+	CurrentID     int64
+	ProfileSelect *widget.Select
+	NewButton     *widget.Button
+	DeleteButton  *widget.Button
+	profileIDs    []int64 // Parallel to ProfileSelect.Options
+	switching     bool    // Suppresses OnChanged while the list is rebuilt
 
 	//Locking logic
 	IsLocked bool
 
 	// Update field funtion for ./calendar.go Refresh()
 	OnSaved func()
+
+	// Asks before the switcher changes the active profile, e.g. to save Calendar edits.
+	// then makes the switch, cancelled keeps the current profile. Nil switches right away
+	ConfirmSwitch func(then, cancelled func())
 }
 
-func NewProfilePage(win fyne.Window, repo *db.Repository) *ProfilePage {
+func NewProfilePage(win fyne.Window, repo *db.Repository, history *UndoHistory) *ProfilePage {
 	p := &ProfilePage{
 		Repo:           repo,
 		Window:         win,
+		History:        history,
 		ScheduleInputs: make(map[int]*widget.Entry),
 	}
 	p.initWidgets()
@@ -98,6 +132,8 @@ func (p *ProfilePage) initWidgets() {
 	p.LastName = widget.NewEntry()
 	p.LastName.SetPlaceHolder("Doe")
 	p.Middle = widget.NewEntry()
+	p.MiddleName = widget.NewEntry()
+	p.MiddleName.SetPlaceHolder("Full-time only")
 	p.EmpID = widget.NewEntry()
 	p.EmpID.SetPlaceHolder("888888888")
 	p.PositionNum = widget.NewEntry()
@@ -160,6 +196,24 @@ func (p *ProfilePage) initWidgets() {
 	)
 	p.SecondaryGroup.Hide()
 
+	// Work-study semester allocation. Later balances carry forward from saved timesheets.
+	p.SemesterStart = widget.NewEntry()
+	p.SemesterStart.SetPlaceHolder("YYYY-MM-DD")
+	p.Allocation = widget.NewEntry()
+	p.Allocation.SetPlaceHolder("300")
+	p.StartingBalance = widget.NewEntry()
+	p.StartingBalance.SetPlaceHolder("Defaults to allocation")
+
+	p.WorkStudyGroup = container.NewVBox(
+		widget.NewLabel("Work-Study Allocation"),
+		widget.NewForm(
+			widget.NewFormItem("Semester Start", p.SemesterStart),
+			widget.NewFormItem("Semester Hours", p.Allocation),
+			widget.NewFormItem("Starting Balance", p.StartingBalance),
+		),
+	)
+	p.WorkStudyGroup.Hide()
+
 	//Dropdown logic
 	p.TypeSelect = widget.NewSelect([]string{
 		string(models.TypeFullTime),
@@ -170,20 +224,27 @@ func (p *ProfilePage) initWidgets() {
 		if selected == string(models.TypeWorkStudy) {
 			p.ExtraGroup.Hide()
 			p.SecondaryGroup.Hide()
+			p.WorkStudyGroup.Show()
 		} else if selected == string(models.TypePartTime) {
 			p.ExtraGroup.Show()
 			p.SecondaryGroup.Show()
+			p.WorkStudyGroup.Hide()
 		} else {
 			// Full-Time
 			p.ExtraGroup.Show()
 			p.SecondaryGroup.Hide()
+			p.WorkStudyGroup.Hide()
 		}
 	})
 
 	//Schedule inputs initialization
 	for i := 0; i < 7; i++ {
 		entry := widget.NewEntry()
-		entry.SetPlaceHolder("08:00-12:00, 13:00-17:00")
+		entry.SetPlaceHolder("8-12, 1-5pm or 22:00-06:00")
+		entry.Validator = func(text string) error {
+			_, err := models.ParseTimeRanges(text)
+			return err
+		}
 		p.ScheduleInputs[i] = entry
 	}
 
@@ -195,6 +256,32 @@ func (p *ProfilePage) initWidgets() {
 	p.EditButton.Disable()
 	p.ExportButton = widget.NewButtonWithIcon("Export", theme.DownloadIcon(), p.exportProfile)
 	p.ImportButton = widget.NewButtonWithIcon("Import", theme.UploadIcon(), p.importProfile)
+	p.RulesButton = widget.NewButtonWithIcon("Overtime Rules", theme.SettingsIcon(), p.editOvertimeRules)
+	p.RoundingButton = widget.NewButtonWithIcon("Hours Rounding", theme.SettingsIcon(), func() {
+		showRoundingDialog(p.Window, p.Repo, p.OnSaved)
+	})
+
+	p.PayChangesLabel = widget.NewLabel("")
+	p.PayChangesButton = widget.NewButtonWithIcon("Rate & FOAP Changes", theme.CalendarIcon(), func() {
+		showPayChangesDialog(p.Window, p.PayChanges, func(changes []models.PayTerms) {
+			p.PayChanges = changes
+			p.updatePayChangesLabel()
+		})
+	})
+
+	p.PayPeriodLabel = widget.NewLabel("")
+	p.PayPeriodButton = widget.NewButtonWithIcon("Pay Period", theme.CalendarIcon(), func() {
+		showPayPeriodDialog(p.Window, p.PayPeriod, func(pp models.PayPeriod) {
+			p.PayPeriod = pp
+			p.updatePayPeriodLabel()
+		})
+	})
+
+	// Profile switcher
+	p.ProfileSelect = widget.NewSelect(nil, p.switchProfile)
+	p.ProfileSelect.PlaceHolder = "No profiles yet"
+	p.NewButton = widget.NewButtonWithIcon("New Profile", theme.ContentAddIcon(), p.newProfile)
+	p.DeleteButton = widget.NewButtonWithIcon("Delete", theme.DeleteIcon(), p.deleteProfile)
 }
 
 func (p *ProfilePage) BuildUI() fyne.CanvasObject {
@@ -204,6 +291,7 @@ func (p *ProfilePage) BuildUI() fyne.CanvasObject {
 		widget.NewFormItem("First Name", p.FirstName),
 		widget.NewFormItem("Last Name", p.LastName),
 		widget.NewFormItem("Middle Initial", p.Middle),
+		widget.NewFormItem("Middle Name", p.MiddleName),
 		widget.NewFormItem("Employee ID", p.EmpID),
 		widget.NewFormItem("Position Number", p.PositionNum),
 	)
@@ -224,17 +312,19 @@ func (p *ProfilePage) BuildUI() fyne.CanvasObject {
 	jobCard := widget.NewCard("Job Details", "", container.NewVBox(
 		jobForm,
 		rateTypeGrid,
+		container.NewBorder(nil, nil, nil, p.PayChangesButton, p.PayChangesLabel),
+		container.NewBorder(nil, nil, nil, p.PayPeriodButton, p.PayPeriodLabel),
 		widget.NewSeparator(),
 		p.ExtraGroup,
 		p.SecondaryGroup,
+		p.WorkStudyGroup,
 	))
 
 	//Schedule form
 
-	days := []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"}
 	scheduleForm := widget.NewForm()
 
-	for i, day := range days {
+	for i, day := range scheduleDays {
 		scheduleForm.Append(day, p.ScheduleInputs[i])
 	}
 
@@ -252,10 +342,18 @@ func (p *ProfilePage) BuildUI() fyne.CanvasObject {
 	// Buttons
 	mainButtons := container.NewGridWithColumns(2, p.SaveButton, p.EditButton)
 	importExportButtons := container.NewGridWithColumns(2, p.ExportButton, p.ImportButton)
-	buttonRow := container.NewVBox(mainButtons, importExportButtons)
+	buttonRow := container.NewVBox(mainButtons, importExportButtons, container.NewGridWithColumns(2, p.RulesButton, p.RoundingButton))
+
+	// Profile switcher, one profile per job held
+	switcherCard := widget.NewCard("Profiles", "Each job gets its own calendar and timesheets", container.NewBorder(
+		nil, nil, nil,
+		container.NewHBox(p.NewButton, p.DeleteButton),
+		p.ProfileSelect,
+	))
 
 	// Assembled layout for profile
 	content := container.NewVBox(
+		switcherCard,
 		personalCard,
 		jobCard,
 		scheduleCard,
@@ -279,20 +377,34 @@ func (p *ProfilePage) LoadData() {
 		dialog.ShowError(err, p.Window)
 		return
 	}
+	p.refreshProfileList()
+
 	if profile == nil {
-		return // No data
+		// No data
+		p.CurrentID = 0
+		p.clearForm()
+		p.unlockForm()
+		return
 	}
 
+	p.CurrentID = profile.ID
+	p.clearForm()
+
 	//Populate fields if data is available
 	p.FirstName.SetText(profile.FirstName)
 	p.LastName.SetText(profile.LastName)
 	p.Middle.SetText(profile.MiddleInitial)
+	p.MiddleName.SetText(profile.MiddleName)
 	p.EmpID.SetText(profile.EmployeeID)
 	p.PositionNum.SetText(profile.PositionNum)
 	p.Dept.SetText(profile.Department)
 	p.Title.SetText(profile.Title)
 	p.Rate.SetText(fmt.Sprintf("%.2f", profile.Rate))
 	p.Location.SetText(profile.Location)
+	p.PayChanges = profile.PayChanges
+	p.updatePayChangesLabel()
+	p.PayPeriod = profile.PayPeriod
+	p.updatePayPeriodLabel()
 
 	// Supervisor and contact info
 	p.SupervisorName.SetText(profile.SupervisorName)
@@ -319,13 +431,22 @@ func (p *ProfilePage) LoadData() {
 		}
 	}
 
+	// Populate work-study allocation. A starting balance of 0 means the hours are used up
+	p.SemesterStart.SetText(profile.SemesterStart)
+	if profile.SemesterAllocation > 0 {
+		p.Allocation.SetText(fmt.Sprintf("%.2f", profile.SemesterAllocation))
+	}
+	if profile.PreviousBalance != nil {
+		p.StartingBalance.SetText(fmt.Sprintf("%.2f", *profile.PreviousBalance))
+	}
+
 	// Populate schedule
 	for dayIdx, schedule := range profile.Schedule {
 		if input, ok := p.ScheduleInputs[dayIdx]; ok {
 			// COnvert range to string
 			var rangeStrs []string
 			for _, r := range schedule.Ranges {
-				rangeStrs = append(rangeStrs, fmt.Sprintf("%s-%s", r.Start, r.End))
+				rangeStrs = append(rangeStrs, r.String())
 			}
 
 			input.SetText(strings.Join(rangeStrs, ", "))
@@ -348,20 +469,11 @@ func (p *ProfilePage) saveData() {
 			continue
 		}
 
-		// Parse string into ranges
-		var ranges []models.TimeRange
-		parts := strings.Split(text, ",")
-		for _, part := range parts {
-			times := strings.Split(strings.TrimSpace(part), "-")
-
-			// Validate time range
-			if len(times) == 2 {
-				// Add to ranges
-				ranges = append(ranges, models.TimeRange{
-					Start: strings.TrimSpace(times[0]),
-					End:   strings.TrimSpace(times[1]),
-				})
-			}
+		// Parse string into ranges, a bad range stops the save
+		ranges, err := models.ParseTimeRanges(text)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("%s schedule: %v", scheduleDays[i], err), p.Window)
+			return
 		}
 
 		// Map to day schedule
@@ -377,11 +489,30 @@ func (p *ProfilePage) saveData() {
 		fmt.Sscanf(p.Rate2.Text, "%f", &rate2)
 	}
 
+	// Parse work-study allocation. An empty starting balance means the full allocation
+	semesterStart := strings.TrimSpace(p.SemesterStart.Text)
+	if semesterStart != "" {
+		if _, err := time.ParseInLocation("2006-01-02", semesterStart, time.Local); err != nil {
+			dialog.ShowError(fmt.Errorf("semester start %q is not YYYY-MM-DD", semesterStart), p.Window)
+			return
+		}
+	}
+	var allocation float64
+	fmt.Sscanf(p.Allocation.Text, "%f", &allocation)
+	var startingBalance *float64
+	if text := strings.TrimSpace(p.StartingBalance.Text); text != "" {
+		var balance float64
+		fmt.Sscanf(text, "%f", &balance)
+		startingBalance = &balance
+	}
+
 	// Create profile model
 	prof := models.Profile{
+		ID:              p.CurrentID,
 		FirstName:       p.FirstName.Text,
 		LastName:        p.LastName.Text,
 		MiddleInitial:   p.Middle.Text,
+		MiddleName:      p.MiddleName.Text,
 		EmployeeID:      p.EmpID.Text,
 		PositionNum:     p.PositionNum.Text,
 		Department:      p.Dept.Text,
@@ -399,7 +530,12 @@ func (p *ProfilePage) saveData() {
 			Account:      p.Acct.Text,
 			Program:      p.Prog.Text,
 		},
-		Schedule: scheduleMap,
+		PayChanges:         p.PayChanges,
+		PayPeriod:          p.PayPeriod,
+		SemesterStart:      semesterStart,
+		SemesterAllocation: allocation,
+		PreviousBalance:    startingBalance,
+		Schedule:           scheduleMap,
 	}
 
 	// Add secondary accounting if any field is filled (for part-time)
@@ -413,19 +549,41 @@ func (p *ProfilePage) saveData() {
 		}
 	}
 
+	// The profile as saved until now, for undo
+	var before *models.Profile
+	if p.CurrentID != 0 {
+		var err error
+		if before, err = p.Repo.GetProfileByID(p.CurrentID); err != nil {
+			dialog.ShowError(err, p.Window)
+			return
+		}
+	}
+
 	// Error saving data
 	if err := p.Repo.SaveProfile(&prof); err != nil {
 		dialog.ShowError(err, p.Window)
 		return
 	}
+	p.recordProfileSave(before, prof)
 
-	//Trigger callback for calendar update
-	if p.OnSaved != nil {
-		p.OnSaved()
-	}
-
-	dialog.ShowInformation("Success", "Profile Saved", p.Window)
+	// Newly created profiles become the active one once Calendar edits are handled.
+	// Cancelling keeps the profile saved but the old one active and on screen
+	p.CurrentID = prof.ID
 	p.lockForm()
+	p.confirmSwitch(func() {
+		if err := p.Repo.SetActiveProfileID(prof.ID); err != nil {
+			dialog.ShowError(err, p.Window)
+			return
+		}
+		p.refreshProfileList()
+
+		//Trigger callback for calendar update
+		if p.OnSaved != nil {
+			p.OnSaved()
+		}
+
+		dialog.ShowInformation("Success", "Profile Saved", p.Window)
+	}, p.LoadData)
 }
 
 func (p *ProfilePage) lockForm() {
@@ -440,6 +598,7 @@ func (p *ProfilePage) lockForm() {
 	p.FirstName.Disable()
 	p.LastName.Disable()
 	p.Middle.Disable()
+	p.MiddleName.Disable()
 	p.EmpID.Disable()
 	p.PositionNum.Disable()
 	p.Dept.Disable()
@@ -460,6 +619,11 @@ func (p *ProfilePage) lockForm() {
 	p.Acct2.Disable()
 	p.Prog2.Disable()
 	p.Rate2.Disable()
+	p.SemesterStart.Disable()
+	p.Allocation.Disable()
+	p.StartingBalance.Disable()
+	p.PayChangesButton.Disable()
+	p.PayPeriodButton.Disable()
 
 	for _, entry := range p.ScheduleInputs {
 		entry.Disable()
@@ -477,8 +641,8 @@ func (p *ProfilePage) exportProfile() {
 		return
 	}
 
-	// Get all timesheets
-	timesheets, err := p.Repo.GetTimesheets()
+	// Get all timesheets for this profile
+	timesheets, err := p.Repo.GetTimesheets(profile.ID)
 	if err != nil {
 		dialog.ShowError(err, p.Window)
 		return
@@ -551,14 +715,20 @@ func (p *ProfilePage) importProfile() {
 		var exportData ProfileExport
 		if err := json.Unmarshal(data, &exportData); err == nil && exportData.Profile.EmployeeID != "" {
 			// New format with timesheets
-			// Save imported profile
+			// Save imported profile as a new job
+			exportData.Profile.ID = 0
 			if err := p.Repo.SaveProfile(&exportData.Profile); err != nil {
 				dialog.ShowError(err, p.Window)
 				return
 			}
+			if err := p.Repo.SetActiveProfileID(exportData.Profile.ID); err != nil {
+				dialog.ShowError(err, p.Window)
+				return
+			}
 
 			// Save imported timesheets
 			for _, timesheet := range exportData.Timesheets {
+				timesheet.ProfileID = exportData.Profile.ID
 				if err := p.Repo.SaveTimesheet(timesheet); err != nil {
 					dialog.ShowError(fmt.Errorf("error importing timesheet: %w", err), p.Window)
 					// Continue importing other timesheets
@@ -574,11 +744,16 @@ func (p *ProfilePage) importProfile() {
 				return
 			}
 
-			// Save imported profile
+			// Save imported profile as a new job
+			profile.ID = 0
 			if err := p.Repo.SaveProfile(&profile); err != nil {
 				dialog.ShowError(err, p.Window)
 				return
 			}
+			if err := p.Repo.SetActiveProfileID(profile.ID); err != nil {
+				dialog.ShowError(err, p.Window)
+				return
+			}
 
 			dialog.ShowInformation("Success", "Profile imported successfully", p.Window)
 		}
@@ -609,6 +784,7 @@ func (p *ProfilePage) unlockForm() {
 	p.FirstName.Enable()
 	p.LastName.Enable()
 	p.Middle.Enable()
+	p.MiddleName.Enable()
 	p.EmpID.Enable()
 	p.PositionNum.Enable()
 	p.Dept.Enable()
@@ -629,9 +805,199 @@ func (p *ProfilePage) unlockForm() {
 	p.Acct2.Enable()
 	p.Prog2.Enable()
 	p.Rate2.Enable()
+	p.SemesterStart.Enable()
+	p.Allocation.Enable()
+	p.StartingBalance.Enable()
+	p.PayChangesButton.Enable()
+	p.PayPeriodButton.Enable()
 
 	// Schedule fields
 	for _, entry := range p.ScheduleInputs {
 		entry.Enable()
 	}
 }
+
+// Rebuild the switcher options and select the active profile
+func (p *ProfilePage) refreshProfileList() {
+	profiles, err := p.Repo.GetProfiles()
+	if err != nil {
+		dialog.ShowError(err, p.Window)
+		return
+	}
+
+	active, err := p.Repo.GetProfile()
+	if err != nil {
+		dialog.ShowError(err, p.Window)
+		return
+	}
+
+	options := make([]string, 0, len(profiles))
+	p.profileIDs = p.profileIDs[:0]
+	selected := ""
+	for _, prof := range profiles {
+		label := fmt.Sprintf("%s %s - %s (#%d)", prof.FirstName, prof.LastName, prof.Type, prof.ID)
+		options = append(options, label)
+		p.profileIDs = append(p.profileIDs, prof.ID)
+		if active != nil && prof.ID == active.ID {
+			selected = label
+		}
+	}
+
+	p.switching = true
+	p.ProfileSelect.SetOptions(options)
+	if selected != "" {
+		p.ProfileSelect.SetSelected(selected)
+	} else {
+		p.ProfileSelect.ClearSelected()
+	}
+	p.switching = false
+
+	if len(profiles) == 0 {
+		p.DeleteButton.Disable()
+	} else {
+		p.DeleteButton.Enable()
+	}
+}
+
+// Switcher callback, makes the chosen profile active across all tabs
+func (p *ProfilePage) switchProfile(selected string) {
+	if p.switching {
+		return
+	}
+
+	idx := p.ProfileSelect.SelectedIndex()
+	if idx < 0 || idx >= len(p.profileIDs) {
+		return
+	}
+
+	id := p.profileIDs[idx]
+	switchTo := func() {
+		if err := p.Repo.SetActiveProfileID(id); err != nil {
+			dialog.ShowError(err, p.Window)
+			p.refreshProfileList()
+			return
+		}
+
+		p.LoadData()
+
+		// Trigger callback for calendar update
+		if p.OnSaved != nil {
+			p.OnSaved()
+		}
+	}
+
+	// Cancelling puts the switcher back on the profile still active
+	p.confirmSwitch(switchTo, p.refreshProfileList)
+}
+
+// Helper running then once ConfirmSwitch allows the active profile to change
+func (p *ProfilePage) confirmSwitch(then, cancelled func()) {
+	if p.ConfirmSwitch == nil {
+		then()
+		return
+	}
+	p.ConfirmSwitch(then, cancelled)
+}
+
+// Start an empty form for another job. It is created on Save.
+func (p *ProfilePage) newProfile() {
+	p.CurrentID = 0
+	p.clearForm()
+
+	p.switching = true
+	p.ProfileSelect.ClearSelected()
+	p.switching = false
+
+	p.unlockForm()
+}
+
+func (p *ProfilePage) deleteProfile() {
+	if p.CurrentID == 0 {
+		return
+	}
+
+	msg := "Delete this profile and all of its timesheets? This cannot be undone."
+	dialog.ShowConfirm("Delete Profile", msg, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		// Calendar edits are saved or dropped first, so nothing is written under the
+		// deleted profile afterwards
+		id := p.CurrentID
+		p.confirmSwitch(func() {
+			if err := p.Repo.DeleteProfile(id); err != nil {
+				dialog.ShowError(err, p.Window)
+				return
+			}
+			p.History.Forget(profileScope(id))
+
+			// Fall back to whichever profile is left
+			if err := p.Repo.SetActiveProfileID(0); err != nil {
+				dialog.ShowError(err, p.Window)
+				return
+			}
+
+			p.LoadData()
+			if p.OnSaved != nil {
+				p.OnSaved()
+			}
+		}, nil)
+	}, p.Window)
+}
+
+// Reset every form field
+func (p *ProfilePage) clearForm() {
+	for _, entry := range []*widget.Entry{
+		p.FirstName, p.LastName, p.Middle, p.MiddleName, p.EmpID, p.PositionNum,
+		p.Dept, p.Title, p.Rate, p.Location,
+		p.SupervisorName, p.SupervisorPhone, p.EmployeePhone, p.OfficePhone,
+		p.Fund, p.Org, p.Acct, p.Prog,
+		p.Fund2, p.Org2, p.Acct2, p.Prog2, p.Rate2,
+		p.SemesterStart, p.Allocation, p.StartingBalance,
+	} {
+		entry.SetText("")
+	}
+
+	for _, entry := range p.ScheduleInputs {
+		entry.SetText("")
+	}
+
+	p.PayChanges = nil
+	p.updatePayChangesLabel()
+	p.PayPeriod = models.PayPeriod{}
+	p.updatePayPeriodLabel()
+
+	p.TypeSelect.ClearSelected()
+	p.ExtraGroup.Hide()
+	p.SecondaryGroup.Hide()
+	p.WorkStudyGroup.Hide()
+}
+
+// Schedule input labels, index matches Profile.Schedule
+var scheduleDays = []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"}
+
+// Edit the overtime policy for the selected employee type
+func (p *ProfilePage) editOvertimeRules() {
+	if p.TypeSelect.Selected == "" {
+		dialog.ShowInformation("Overtime Rules", "Select an employee type first", p.Window)
+		return
+	}
+
+	showOvertimeRulesDialog(p.Window, p.Repo, models.EmployeeType(p.TypeSelect.Selected), p.OnSaved)
+}
+
+// Show how many dated pay changes the profile has and the latest one
+func (p *ProfilePage) updatePayChangesLabel() {
+	if len(p.PayChanges) == 0 {
+		p.PayChangesLabel.SetText("No rate or FOAP changes")
+		return
+	}
+	latest := p.PayChanges[len(p.PayChanges)-1]
+	p.PayChangesLabel.SetText(fmt.Sprintf("%d change(s), latest $%.2f/hr from %s", len(p.PayChanges), latest.Rate, latest.EffectiveDate))
+}
+
+// Show the pay period definition
+func (p *ProfilePage) updatePayPeriodLabel() {
+	p.PayPeriodLabel.SetText(payPeriodSummary(p.PayPeriod))
+}
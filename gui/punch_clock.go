@@ -0,0 +1,228 @@
+package gui
+
+import (
+	"fmt"
+	"time"
+
+	"calendar_utility_node_for_timesheets/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Clock In / Clock Out control for the Calendar tab. Punches are recorded on
+// today's DayCell and that day alone is saved right away, so a running clock survives
+// a restart.
+
+// Create the clock button and timer label
+func (c *CalendarPage) initClock() {
+	c.TimerLabel = widget.NewLabelWithStyle("", fyne.TextAlignCenter, fyne.TextStyle{Monospace: true})
+	c.ClockBtn = widget.NewButtonWithIcon("Clock In", theme.MediaPlayIcon(), c.toggleClock)
+}
+
+// Punch in or out at the current time
+func (c *CalendarPage) toggleClock() {
+	if c.Profile == nil {
+		dialog.ShowInformation("No Profile", "Create a profile before clocking in", c.Window)
+		return
+	}
+
+	// Clock out on the sheet of the open punch, which may be the previous period's
+	// for a night shift. New punches go on the current period's sheet
+	now := time.Now()
+	target := now
+	if start, ok := c.openPunchStart(); ok {
+		target = start
+	}
+	if !c.Period.Contains(target.Format("2006-01-02")) {
+		c.ConfirmLeave(func() {
+			c.CurrentDate = target
+			c.Refresh()
+			c.punch(now)
+		})
+		return
+	}
+	c.punch(now)
+}
+
+// Record a punch at now on the sheet on screen and save that day alone. Other edits
+// on screen stay unsaved, so a bad input elsewhere cannot stop the punch being saved
+func (c *CalendarPage) punch(now time.Time) {
+	if c.locked() {
+		dialog.ShowInformation("Timesheet Locked", "This timesheet is "+string(c.Status)+". Reopen it to record punches.", c.Window)
+		return
+	}
+
+	stamp := now.Format("15:04")
+	cell, idx := c.openPunchCell()
+	if cell == nil {
+		var ok bool
+		if cell, ok = c.DayWidgets[now.Format("2006-01-02")]; !ok {
+			dialog.ShowInformation("Punch Not Recorded", now.Format("Mon Jan 2")+" is not on the timesheet on screen.", c.Window)
+			return
+		}
+	}
+	if cell.closed {
+		dialog.ShowInformation("Punch Not Recorded", fmt.Sprintf("%s is closed for %s, so it has no worked hours to punch.",
+			cell.DateStr, cell.Closure), c.Window)
+		return
+	}
+	if err := cell.Validate(); err != nil {
+		dialog.ShowError(fmt.Errorf("punch not recorded, fix %s first: %v", cell.DateStr, err), c.Window)
+		return
+	}
+
+	before := cell.GetData()
+	punches := append([]models.TimeRange(nil), cell.Punches...)
+	if idx >= 0 {
+		punches[idx].End = stamp
+		cell.SetPunches(punches)
+	} else {
+		cell.SetPunches(append(punches, models.TimeRange{Start: stamp}))
+	}
+
+	if err := c.storePunch(cell); err != nil {
+		c.replaying = true
+		cell.SetData(before)
+		c.replaying = false
+		c.recalculateLive()
+		dialog.ShowError(fmt.Errorf("punch not recorded: %v", err), c.Window)
+	}
+	c.syncClock()
+}
+
+// Save the sheet as last saved with one day's entry from the cell
+func (c *CalendarPage) storePunch(cell *DayCell) error {
+	cell.ApplyRounding()
+	entries := make(map[string]models.DailyEntry, len(c.DayWidgets))
+	for date, other := range c.DayWidgets {
+		entries[date] = other.saved
+	}
+	entries[cell.DateStr] = cell.GetData()
+
+	if err := c.storeEntries(entries, ""); err != nil {
+		return err
+	}
+	cell.MarkSaved()
+	c.savedOtherDesc = c.OtherPaidDescEntry.Text
+
+	// Saving cleared the journal, draft whatever else is still unsaved
+	c.edited()
+	return nil
+}
+
+// Find the day on screen that is still clocked in
+func (c *CalendarPage) openPunchCell() (*DayCell, int) {
+	for _, cell := range c.DayWidgets {
+		if idx := (models.DailyEntry{Punches: cell.Punches}).OpenPunch(); idx >= 0 {
+			return cell, idx
+		}
+	}
+	return nil, -1
+}
+
+// Find when the current open punch started. Looks at the cells on screen, then at the
+// saved sheets covering today and yesterday, where a night shift may have begun
+func (c *CalendarPage) openPunchStart() (time.Time, bool) {
+	for dateStr, cell := range c.DayWidgets {
+		if start, ok := punchStart(dateStr, models.DailyEntry{Punches: cell.Punches}); ok {
+			return start, true
+		}
+	}
+
+	today := time.Now()
+	for _, day := range []time.Time{today, today.AddDate(0, 0, -1)} {
+		if c.Period.Contains(day.Format("2006-01-02")) {
+			continue // Already checked the cells
+		}
+		ts, err := c.Repo.GetTimesheetOn(c.Profile.ID, day)
+		if err != nil || ts == nil {
+			continue
+		}
+		for dateStr, day := range ts.Entries {
+			if start, ok := punchStart(dateStr, day); ok {
+				return start, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// Helper returning when a day's open punch started
+func punchStart(dateStr string, day models.DailyEntry) (time.Time, bool) {
+	idx := day.OpenPunch()
+	if idx < 0 {
+		return time.Time{}, false
+	}
+	start, err := time.ParseInLocation("2006-01-02 15:04", dateStr+" "+day.Punches[idx].Start, time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return start, true
+}
+
+// Update the button and timer to match the punches
+func (c *CalendarPage) syncClock() {
+	c.stopTimer()
+
+	if c.Profile == nil {
+		c.ClockBtn.Disable()
+		c.TimerLabel.SetText("")
+		return
+	}
+	c.ClockBtn.Enable()
+
+	start, ok := c.openPunchStart()
+	if !ok {
+		c.ClockBtn.SetText("Clock In")
+		c.ClockBtn.SetIcon(theme.MediaPlayIcon())
+		c.TimerLabel.SetText("")
+		return
+	}
+
+	c.ClockBtn.SetText("Clock Out")
+	c.ClockBtn.SetIcon(theme.MediaStopIcon())
+	c.startTimer(start)
+}
+
+// Tick the timer label once a second until stopped
+func (c *CalendarPage) startTimer(since time.Time) {
+	stop := make(chan struct{})
+	c.clockStop = stop
+
+	update := func() {
+		c.TimerLabel.SetText(fmt.Sprintf("In since %s  %s", since.Format("15:04"), formatElapsed(time.Since(since))))
+	}
+	update()
+
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				fyne.Do(update)
+			}
+		}
+	}()
+}
+
+func (c *CalendarPage) stopTimer() {
+	if c.clockStop != nil {
+		close(c.clockStop)
+		c.clockStop = nil
+	}
+}
+
+// Helper to format a duration as H:MM:SS
+func formatElapsed(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	secs := int(d.Seconds())
+	return fmt.Sprintf("%d:%02d:%02d", secs/3600, secs/60%60, secs%60)
+}
@@ -0,0 +1,68 @@
+package gui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"calendar_utility_node_for_timesheets/db"
+	"calendar_utility_node_for_timesheets/validation"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Labels for the rounding modes
+var roundModeLabels = map[validation.RoundMode]string{
+	validation.RoundNearest: "Nearest",
+	validation.RoundUp:      "Always up",
+	validation.RoundDown:    "Always down",
+	validation.RoundOff:     "Don't round",
+}
+
+// showRoundingDialog edits the rounding rule applied to every hours input
+func showRoundingDialog(win fyne.Window, repo *db.Repository, onSaved func()) {
+	rule := validation.CurrentRounding()
+
+	options := make([]string, len(validation.RoundModes))
+	for i, mode := range validation.RoundModes {
+		options[i] = roundModeLabels[mode]
+	}
+	modeSelect := widget.NewSelect(options, nil)
+	modeSelect.SetSelected(roundModeLabels[rule.Mode])
+
+	increment := widget.NewEntry()
+	increment.SetText(strconv.FormatFloat(rule.Increment, 'f', -1, 64))
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Round", modeSelect),
+		widget.NewFormItem("To Increment (hrs)", increment),
+	}
+
+	dialog.ShowForm("Hours Rounding", "Save", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		var updated validation.Rounding
+		var err error
+		if updated.Increment, err = strconv.ParseFloat(strings.TrimSpace(increment.Text), 64); err != nil || updated.Increment <= 0 || updated.Increment > 1 {
+			dialog.ShowError(fmt.Errorf("increment must be a number between 0 and 1, e.g. 0.25"), win)
+			return
+		}
+		updated.Mode = validation.RoundModes[0]
+		if idx := modeSelect.SelectedIndex(); idx >= 0 {
+			updated.Mode = validation.RoundModes[idx]
+		}
+
+		if err := repo.SaveRounding(updated); err != nil {
+			dialog.ShowError(err, win)
+			return
+		}
+
+		if onSaved != nil {
+			onSaved()
+		}
+	}, win)
+}
@@ -0,0 +1,317 @@
+package gui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"calendar_utility_node_for_timesheets/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Steps kept before the oldest are dropped
+const maxUndoSteps = 200
+
+// UndoHistory is the undo/redo stack shared by the Calendar and Profile tabs. There is
+// one per window, so it survives switching tabs.
+type UndoHistory struct {
+	done   []undoStep
+	undone []undoStep
+	sealed bool // The next step starts fresh instead of merging into the last
+
+	watchers []func()
+}
+
+// One undoable change. undo and redo put the state back as it was before and after it;
+// a step whose function fails stays where it is
+type undoStep struct {
+	label string
+	scope string // What the step changes, see Forget
+	key   string // Consecutive steps with the same key merge, e.g. typing in one day
+	undo  func() error
+	redo  func() error
+}
+
+func NewUndoHistory() *UndoHistory {
+	return &UndoHistory{}
+}
+
+// AddShortcuts binds Ctrl+Z to undo and Ctrl+Shift+Z (or Ctrl+Y) to redo. While a text
+// field has focus it handles the keys itself, undoing typing in that field
+func (h *UndoHistory) AddShortcuts(win fyne.Window) {
+	undo := func(fyne.Shortcut) {
+		if err := h.Undo(); err != nil {
+			dialog.ShowError(err, win)
+		}
+	}
+	redo := func(fyne.Shortcut) {
+		if err := h.Redo(); err != nil {
+			dialog.ShowError(err, win)
+		}
+	}
+
+	win.Canvas().AddShortcut(&fyne.ShortcutUndo{}, undo)
+	win.Canvas().AddShortcut(&fyne.ShortcutRedo{}, redo)
+	win.Canvas().AddShortcut(&desktop.CustomShortcut{
+		KeyName:  fyne.KeyZ,
+		Modifier: fyne.KeyModifierShortcutDefault | fyne.KeyModifierShift,
+	}, redo)
+}
+
+// Watch calls f whenever what can be undone or redone changes
+func (h *UndoHistory) Watch(f func()) {
+	h.watchers = append(h.watchers, f)
+}
+
+// Record adds a step that was just done and clears the redo list
+func (h *UndoHistory) Record(s undoStep) {
+	h.undone = nil
+
+	if n := len(h.done); n > 0 && !h.sealed && s.key != "" {
+		top := &h.done[n-1]
+		if top.key == s.key && top.scope == s.scope {
+			top.redo = s.redo
+			h.changed()
+			return
+		}
+	}
+	h.sealed = false
+
+	h.done = append(h.done, s)
+	if len(h.done) > maxUndoSteps {
+		h.done = h.done[len(h.done)-maxUndoSteps:]
+	}
+	h.changed()
+}
+
+// Undo reverts the latest step
+func (h *UndoHistory) Undo() error {
+	n := len(h.done)
+	if n == 0 {
+		return nil
+	}
+	s := h.done[n-1]
+	if err := s.undo(); err != nil {
+		return fmt.Errorf("cannot undo %s: %w", s.label, err)
+	}
+
+	h.done = h.done[:n-1]
+	h.undone = append(h.undone, s)
+	h.sealed = true
+	h.changed()
+	return nil
+}
+
+// Redo applies the latest undone step again
+func (h *UndoHistory) Redo() error {
+	n := len(h.undone)
+	if n == 0 {
+		return nil
+	}
+	s := h.undone[n-1]
+	if err := s.redo(); err != nil {
+		return fmt.Errorf("cannot redo %s: %w", s.label, err)
+	}
+
+	h.undone = h.undone[:n-1]
+	h.done = append(h.done, s)
+	h.sealed = true
+	h.changed()
+	return nil
+}
+
+// CanUndo reports whether there is a step to undo
+func (h *UndoHistory) CanUndo() bool {
+	return len(h.done) > 0
+}
+
+// CanRedo reports whether there is a step to redo
+func (h *UndoHistory) CanRedo() bool {
+	return len(h.undone) > 0
+}
+
+// Forget drops every step whose scope starts with prefix, e.g. once the sheet or
+// profile it changed is gone from the screen
+func (h *UndoHistory) Forget(prefix string) {
+	keep := func(steps []undoStep) []undoStep {
+		var kept []undoStep
+		for _, s := range steps {
+			if !strings.HasPrefix(s.scope, prefix) {
+				kept = append(kept, s)
+			}
+		}
+		return kept
+	}
+	h.done = keep(h.done)
+	h.undone = keep(h.undone)
+	h.sealed = true
+	h.changed()
+}
+
+// Helper to notify watchers
+func (h *UndoHistory) changed() {
+	for _, f := range h.watchers {
+		f()
+	}
+}
+
+// Scopes nest, so forgetting a profile also forgets its sheets
+func profileScope(id int64) string {
+	return fmt.Sprintf("profile:%d/", id)
+}
+
+func sheetScope(profileID int64, period models.Period) string {
+	return profileScope(profileID) + "sheet:" + period.Key()
+}
+
+/* CALENDAR */
+
+// Create the toolbar buttons and follow the history
+func (c *CalendarPage) initUndo() {
+	c.UndoBtn = widget.NewButtonWithIcon("", theme.ContentUndoIcon(), func() {
+		if err := c.History.Undo(); err != nil {
+			dialog.ShowError(err, c.Window)
+		}
+	})
+	c.RedoBtn = widget.NewButtonWithIcon("", theme.ContentRedoIcon(), func() {
+		if err := c.History.Redo(); err != nil {
+			dialog.ShowError(err, c.Window)
+		}
+	})
+	c.History.Watch(c.syncUndo)
+	c.syncUndo()
+}
+
+// Enable the buttons when there is something to undo or redo
+func (c *CalendarPage) syncUndo() {
+	setEnabled(c.UndoBtn, c.History.CanUndo())
+	setEnabled(c.RedoBtn, c.History.CanRedo())
+}
+
+// Point the history at the sheet on screen. Steps for another sheet can no longer be
+// applied to the cells, so they are dropped
+func (c *CalendarPage) setUndoScope(scope string) {
+	if c.undoScope != "" && c.undoScope != scope {
+		c.History.Forget(c.undoScope)
+	}
+	c.undoScope = scope
+}
+
+// Called for every input change of a cell. Typing in one day is a single step
+func (c *CalendarPage) cellEdited(cell *DayCell) {
+	before := cell.undoBase
+	after := cell.GetData()
+	cell.undoBase = after
+	if c.loading || c.replaying || models.SameEntry(before, after) {
+		return
+	}
+
+	day, _ := time.Parse("2006-01-02", cell.DateStr)
+	c.recordCells("edit "+day.Format("Mon Jan 2"), "day:"+cell.DateStr,
+		map[string]models.DailyEntry{cell.DateStr: before},
+		map[string]models.DailyEntry{cell.DateStr: after})
+}
+
+// fillCells puts entries in their cells as one undoable step. Days not in entries are
+// left alone
+func (c *CalendarPage) fillCells(label string, entries map[string]models.DailyEntry) error {
+	before := make(map[string]models.DailyEntry)
+	changed := false
+	for date, e := range entries {
+		if cell, ok := c.DayWidgets[date]; ok {
+			before[date] = cell.GetData()
+			changed = changed || !models.SameEntry(before[date], e)
+		}
+	}
+	if !changed {
+		return nil
+	}
+	if err := c.applyCells(c.undoScope, entries); err != nil {
+		return err
+	}
+	c.recordCells(label, "", before, entries)
+	return nil
+}
+
+// Helper to add a step that moves cells between two states
+func (c *CalendarPage) recordCells(label, key string, before, after map[string]models.DailyEntry) {
+	scope := c.undoScope
+	c.History.Record(undoStep{
+		label: label,
+		scope: scope,
+		key:   key,
+		undo:  func() error { return c.applyCells(scope, before) },
+		redo:  func() error { return c.applyCells(scope, after) },
+	})
+}
+
+// Helper to set cells without recording them, then update totals and unsaved state
+func (c *CalendarPage) applyCells(scope string, entries map[string]models.DailyEntry) error {
+	if scope != c.undoScope {
+		return errors.New("that timesheet is no longer on screen")
+	}
+	if c.locked() {
+		return fmt.Errorf("this timesheet is %s, reopen it to make changes", c.Status)
+	}
+
+	c.replaying = true
+	for date, e := range entries {
+		if cell, ok := c.DayWidgets[date]; ok {
+			cell.SetData(e)
+		}
+	}
+	c.replaying = false
+
+	c.recalculateLive()
+	c.edited()
+	return nil
+}
+
+/* PROFILE */
+
+// Record a profile save so it can be undone. before is the profile as it was saved
+// until now, nil for a new profile
+func (p *ProfilePage) recordProfileSave(before *models.Profile, after models.Profile) {
+	if before == nil {
+		return
+	}
+	old, _ := json.Marshal(before)
+	updated, _ := json.Marshal(after)
+	if string(old) == string(updated) {
+		return
+	}
+
+	prev := *before
+	p.History.Record(undoStep{
+		label: "profile changes",
+		scope: profileScope(after.ID),
+		undo:  func() error { return p.putProfile(prev) },
+		redo:  func() error { return p.putProfile(after) },
+	})
+}
+
+// Helper to save a profile from the history, make it active and reload every tab
+func (p *ProfilePage) putProfile(prof models.Profile) error {
+	if !p.IsLocked {
+		return errors.New("save or finish editing the profile first")
+	}
+	if err := p.Repo.SaveProfile(&prof); err != nil {
+		return err
+	}
+	if err := p.Repo.SetActiveProfileID(prof.ID); err != nil {
+		return err
+	}
+
+	p.LoadData()
+	if p.OnSaved != nil {
+		p.OnSaved()
+	}
+	return nil
+}
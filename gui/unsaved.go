@@ -0,0 +1,208 @@
+package gui
+
+import (
+	"fmt"
+	"time"
+
+	"calendar_utility_node_for_timesheets/db"
+	"calendar_utility_node_for_timesheets/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Unsaved-changes tracking for the Calendar tab. Edits are compared with the sheet as
+// last loaded or saved; with auto-save on they are also journaled once typing pauses,
+// and a draft left by a crash is put back in the cells the next time the sheet opens.
+
+// Create the indicator and auto-save switch
+func (c *CalendarPage) initUnsaved() {
+	c.AutoSave = db.NewAutoSaver(c.Repo, db.AutoSaveDelay)
+	c.AutoSave.OnError = func(err error) {
+		fyne.Do(func() { c.showAutoSaveError(err) })
+	}
+
+	c.DirtyLabel = widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	c.DirtyLabel.Importance = widget.WarningImportance
+	c.DirtyLabel.Hide()
+
+	on, err := c.Repo.AutoSaveEnabled()
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("failed to load the auto-save setting: %v", err), c.Window)
+	}
+	c.AutoSaveCheck = widget.NewCheck("Auto-save drafts", nil)
+	c.AutoSaveCheck.SetChecked(on)
+	c.AutoSaveCheck.OnChanged = func(on bool) {
+		if err := c.Repo.SetAutoSaveEnabled(on); err != nil {
+			dialog.ShowError(err, c.Window)
+			return
+		}
+		if !on {
+			c.dropDraft()
+		}
+		c.edited()
+	}
+
+	c.OtherPaidDescEntry.OnChanged = func(string) { c.edited() }
+}
+
+// Called on every edit: refresh the indicator and journal or drop the draft
+func (c *CalendarPage) edited() {
+	if c.loading || c.Profile == nil {
+		return
+	}
+	c.updateDirty()
+
+	if !c.dirty() {
+		c.dropDraft()
+		return
+	}
+	if c.AutoSaveCheck.Checked && !c.locked() {
+		c.AutoSave.Queue(c.journalEntry())
+	}
+}
+
+// Whether any input differs from the saved sheet
+func (c *CalendarPage) dirty() bool {
+	if c.OtherPaidDescEntry.Text != c.savedOtherDesc {
+		return true
+	}
+	for _, cell := range c.DayWidgets {
+		if cell.Dirty() {
+			return true
+		}
+	}
+	return false
+}
+
+// Show or hide the unsaved changes indicator
+func (c *CalendarPage) updateDirty() {
+	if c.Profile == nil || !c.dirty() {
+		c.DirtyLabel.Hide()
+		return
+	}
+
+	text := "Unsaved changes"
+	if !c.recoveredAt.IsZero() {
+		text += " (recovered from " + c.recoveredAt.Local().Format("Jan 2 15:04") + ")"
+	}
+	c.DirtyLabel.SetText(text)
+	c.DirtyLabel.Show()
+}
+
+// Make the inputs on screen the saved state
+func (c *CalendarPage) markSaved() {
+	for _, cell := range c.DayWidgets {
+		cell.MarkSaved()
+	}
+	c.savedOtherDesc = c.OtherPaidDescEntry.Text
+	c.recoveredAt = time.Time{}
+	c.updateDirty()
+}
+
+// The cells as a journal draft
+func (c *CalendarPage) journalEntry() models.JournalEntry {
+	return models.JournalEntry{
+		ProfileID:            c.Profile.ID,
+		PeriodStart:          c.Period.Key(),
+		PeriodEnd:            c.Period.EndKey(),
+		Entries:              c.collectEntries(),
+		OtherPaidDescription: c.OtherPaidDescEntry.Text,
+	}
+}
+
+// Drop the pending and journaled draft of the sheet on screen
+func (c *CalendarPage) dropDraft() {
+	c.AutoSave.Cancel()
+	if c.Profile == nil {
+		return
+	}
+	if err := c.Repo.ClearJournal(c.Profile.ID, c.Period); err != nil {
+		c.showAutoSaveError(err)
+	}
+}
+
+// Put a journaled draft of this sheet back in the cells. Drafts that match the saved
+// sheet are dropped
+func (c *CalendarPage) restoreDraft() {
+	j, err := c.Repo.GetJournal(c.Profile.ID, c.Period)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("failed to load unsaved changes: %v", err), c.Window)
+		return
+	}
+	if j == nil {
+		return
+	}
+
+	c.recoveredAt = j.SavedAt
+	if c.Profile.Type == models.TypeFullTime {
+		c.loading = true
+		c.OtherPaidDescEntry.SetText(j.OtherPaidDescription)
+		c.loading = false
+	}
+
+	// The recovery is one step, undoing it puts the saved sheet back
+	if err := c.fillCells("recover unsaved changes", j.Entries); err != nil {
+		dialog.ShowError(fmt.Errorf("failed to recover unsaved changes: %v", err), c.Window)
+	}
+	if !c.dirty() {
+		c.recoveredAt = time.Time{}
+		c.dropDraft()
+	}
+}
+
+// Helper to report a failed journal write. The edits are still on screen
+func (c *CalendarPage) showAutoSaveError(err error) {
+	dialog.ShowError(fmt.Errorf("auto-save failed, your changes are not saved yet: %v", err), c.Window)
+}
+
+// ConfirmLeave runs then once the edits on screen are safe to throw away: right away
+// when nothing changed, otherwise after the user saves or discards them
+func (c *CalendarPage) ConfirmLeave(then func()) {
+	c.ConfirmLeaveOrCancel(then, nil)
+}
+
+// ConfirmLeaveOrCancel is ConfirmLeave that runs cancelled, when not nil, if the user
+// cancels or the save fails
+func (c *CalendarPage) ConfirmLeaveOrCancel(then, cancelled func()) {
+	if c.Profile == nil || c.locked() || !c.dirty() {
+		// Nothing to keep, and no queued draft may land after then runs
+		c.AutoSave.Cancel()
+		then()
+		return
+	}
+	stay := func() {
+		if cancelled != nil {
+			cancelled()
+		}
+	}
+
+	var d *dialog.CustomDialog
+	saveBtn := widget.NewButtonWithIcon("Save", theme.DocumentSaveIcon(), func() {
+		d.Hide()
+		if err := c.storeTimesheet(); err != nil {
+			dialog.ShowError(err, c.Window)
+			stay()
+			return
+		}
+		then()
+	})
+	saveBtn.Importance = widget.HighImportance
+	discardBtn := widget.NewButtonWithIcon("Discard", theme.DeleteIcon(), func() {
+		d.Hide()
+		c.dropDraft()
+		c.markSaved()
+		then()
+	})
+	cancelBtn := widget.NewButtonWithIcon("Cancel", theme.CancelIcon(), func() {
+		d.Hide()
+		stay()
+	})
+
+	msg := widget.NewLabel(fmt.Sprintf("Save your changes to the %s timesheet?", c.Period.Label()))
+	d = dialog.NewCustomWithoutButtons("Unsaved Changes", msg, c.Window)
+	d.SetButtons([]fyne.CanvasObject{cancelBtn, discardBtn, saveBtn})
+	d.Show()
+}
@@ -2,8 +2,6 @@ package main
 
 import (
 	"log"
-	"os"
-	"path/filepath"
 
 	"calendar_utility_node_for_timesheets/db"
 	"calendar_utility_node_for_timesheets/gui"
@@ -22,33 +20,48 @@ func main() {
 	myWindow := myApp.NewWindow("Calendar Utility Node for Timesheets")
 
 	//DB SETUP
-	configDir, _ := os.UserConfigDir()
-	appPath := filepath.Join(configDir, "Timesheets")
-	os.MkdirAll(appPath, 0755)
-
-	repo, err := db.NewRepository(appPath)
+	repo, err := db.OpenDefault()
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	//Setup Pages
-	profilePage := gui.NewProfilePage(myWindow, repo)
-	calendarPage := gui.NewCalendarPage(myWindow, repo)
+	//Setup Pages, sharing one undo history
+	history := gui.NewUndoHistory()
+	history.AddShortcuts(myWindow)
+	profilePage := gui.NewProfilePage(myWindow, repo, history)
+	calendarPage := gui.NewCalendarPage(myWindow, repo, history)
+	leavePage := gui.NewLeavePage(myWindow, repo)
 
 	//Load data on startup
 	profilePage.LoadData()
 
 	//Load calendar data
 	profilePage.OnSaved = func() {
-		calendarPage.Refresh()
+		// Offer to save Calendar edits before they are redrawn
+		calendarPage.ConfirmLeave(calendarPage.Refresh)
+		leavePage.Refresh()
 	}
+	profilePage.ConfirmSwitch = calendarPage.ConfirmLeaveOrCancel
 
 	//Layout for tabs
 	tabs := container.NewAppTabs(
 		container.NewTabItem("Profile", profilePage.BuildUI()),
 		container.NewTabItem("Calendar", calendarPage.BuildUI()), //placeholder for calendar tab
+		container.NewTabItem("Leave", leavePage.BuildUI()),
 	)
 
+	// Usage comes from saved timesheets, reload the ledger when the tab is opened
+	tabs.OnSelected = func(tab *container.TabItem) {
+		if tab.Text == "Leave" {
+			leavePage.Refresh()
+		}
+	}
+
+	// Unsaved Calendar edits are saved or discarded before the window closes
+	myWindow.SetCloseIntercept(func() {
+		calendarPage.ConfirmLeave(myWindow.Close)
+	})
+
 	myWindow.SetContent(tabs)
 	myWindow.Resize(fyne.NewSize(800, 600))
 	myWindow.ShowAndRun()
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Holiday is a college holiday or closure day
+type Holiday struct {
+	Date string `json:"date"` // 2006-01-02
+	Name string `json:"name"`
+}
+
+// ParseHolidays reads a holiday file. Either a JSON array of {"date", "name"}
+// objects or CSV lines of "date,name" (a header row and # comments are skipped)
+func ParseHolidays(data []byte) ([]Holiday, error) {
+	var holidays []Holiday
+
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("[")) {
+		if err := json.Unmarshal(trimmed, &holidays); err != nil {
+			return nil, fmt.Errorf("invalid holiday JSON: %w", err)
+		}
+	} else {
+		r := csv.NewReader(bytes.NewReader(trimmed))
+		r.Comment = '#'
+		r.FieldsPerRecord = -1
+		r.TrimLeadingSpace = true
+		for line := 1; ; line++ {
+			rec, err := r.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid holiday CSV: %w", err)
+			}
+			if line == 1 && strings.EqualFold(strings.TrimSpace(rec[0]), "date") {
+				continue // Header
+			}
+
+			h := Holiday{Date: strings.TrimSpace(rec[0])}
+			if len(rec) > 1 {
+				h.Name = strings.TrimSpace(rec[1])
+			}
+			holidays = append(holidays, h)
+		}
+	}
+
+	for i, h := range holidays {
+		if _, err := time.Parse("2006-01-02", h.Date); err != nil {
+			return nil, fmt.Errorf("holiday %d: date %q is not YYYY-MM-DD", i+1, h.Date)
+		}
+		if holidays[i].Name == "" {
+			holidays[i].Name = "Closed"
+		}
+	}
+	return holidays, nil
+}
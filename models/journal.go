@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// JournalEntry is the unsaved state of one timesheet on the Calendar tab, kept by the
+// auto-save journal so a crash loses no typing. It is cleared when the sheet is saved
+// or the edits are discarded.
+type JournalEntry struct {
+	ProfileID            int64                 `json:"profile_id"`
+	PeriodStart          string                `json:"period_start"` // Identifies the sheet, see Timesheet.Period
+	PeriodEnd            string                `json:"period_end"`
+	SavedAt              time.Time             `json:"saved_at"`
+	Entries              map[string]DailyEntry `json:"entries"`
+	OtherPaidDescription string                `json:"other_paid_description,omitempty"`
+}
@@ -0,0 +1,49 @@
+package models
+
+// LeaveType is a kind of paid leave tracked in the leave ledger
+type LeaveType string
+
+const (
+	LeaveSick     LeaveType = "sick"
+	LeaveVacation LeaveType = "vacation"
+	LeaveComp     LeaveType = "comp"
+)
+
+// LeaveTypes in display order
+var LeaveTypes = []LeaveType{LeaveSick, LeaveVacation, LeaveComp}
+
+// LeaveAccount is a profile's starting balance and accrual for one leave type
+type LeaveAccount struct {
+	ProfileID       int64     `json:"profile_id"`
+	Type            LeaveType `json:"type"`
+	StartingBalance float64   `json:"starting_balance"`
+	AccrualRate     float64   `json:"accrual_rate"` // Hours added each month
+
+	// First month the starting balance applies to
+	StartMonth int `json:"start_month"`
+	StartYear  int `json:"start_year"`
+}
+
+// LeaveLedgerLine is one month of movement on a leave account
+type LeaveLedgerLine struct {
+	Month   int     `json:"month"`
+	Year    int     `json:"year"`
+	Opening float64 `json:"opening"`
+	Accrued float64 `json:"accrued"`
+	Earned  float64 `json:"earned"` // Comp time earned from overtime
+	Used    float64 `json:"used"`
+	Balance float64 `json:"balance"`
+}
+
+// LeaveUsed returns the hours of a leave type taken on the day
+func (d DailyEntry) LeaveUsed(t LeaveType) float64 {
+	switch t {
+	case LeaveSick:
+		return d.SickLeave
+	case LeaveVacation:
+		return d.Vacation
+	case LeaveComp:
+		return d.CompTimeTaken
+	}
+	return 0
+}
@@ -0,0 +1,124 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// PayTerms are the hourly rate and accounting codes in force from EffectiveDate on
+type PayTerms struct {
+	EffectiveDate       string           `json:"effective_date"` // 2006-01-02, empty for the profile's base terms
+	Rate                float64          `json:"rate"`
+	PrimaryAccounting   AccountingCodes  `json:"primary_accounting"`
+	SecondaryAccounting *AccountingCodes `json:"secondary_accounting,omitempty"`
+}
+
+// TermsSegment is a run of days in a pay period that share the same pay terms
+type TermsSegment struct {
+	From  time.Time
+	To    time.Time
+	Terms PayTerms
+}
+
+// BaseTerms are the profile's own rate and codes, in force before the first dated change
+func (p *Profile) BaseTerms() PayTerms {
+	return PayTerms{
+		Rate:                p.Rate,
+		PrimaryAccounting:   p.PrimaryAccounting,
+		SecondaryAccounting: p.SecondaryAccounting,
+	}
+}
+
+// TermsOn returns the pay terms in force on a date (2006-01-02): the latest change
+// effective on or before it, otherwise the base terms
+func (p *Profile) TermsOn(date string) PayTerms {
+	terms := p.BaseTerms()
+	for _, change := range p.sortedPayChanges() {
+		if change.EffectiveDate > date {
+			break
+		}
+		terms = change
+	}
+	return terms
+}
+
+// MonthSegments splits a month into runs of days with the same pay terms. A month with
+// no change in it is one segment.
+func (p *Profile) MonthSegments(year int, month time.Month) []TermsSegment {
+	return p.PeriodSegments(MonthPeriod(year, month))
+}
+
+// PeriodSegments splits a pay period into runs of days with the same pay terms
+func (p *Profile) PeriodSegments(period Period) []TermsSegment {
+	first, last := period.Start, period.End
+
+	segments := []TermsSegment{{From: first, To: last, Terms: p.TermsOn(first.Format("2006-01-02"))}}
+	for _, change := range p.sortedPayChanges() {
+		eff, err := time.ParseInLocation("2006-01-02", change.EffectiveDate, time.Local)
+		if err != nil || !eff.After(first) || eff.After(last) {
+			continue
+		}
+
+		// Close the running segment the day before the change
+		segments[len(segments)-1].To = eff.AddDate(0, 0, -1)
+		segments = append(segments, TermsSegment{From: eff, To: last, Terms: change})
+	}
+	return segments
+}
+
+// Contains reports whether a date (2006-01-02) falls in the segment
+func (s TermsSegment) Contains(date string) bool {
+	return date >= s.From.Format("2006-01-02") && date <= s.To.Format("2006-01-02")
+}
+
+// HoursWorked totals the worked hours of the entries that fall in the segment
+func (s TermsSegment) HoursWorked(entries map[string]DailyEntry) float64 {
+	var total float64
+	for date, e := range entries {
+		if s.Contains(date) {
+			total += e.HoursWorked
+		}
+	}
+	return total
+}
+
+// SplitHours totals the entries in the segment by accounting line
+func (s TermsSegment) SplitHours(entries map[string]DailyEntry) (primary, secondary float64) {
+	for date, e := range entries {
+		if s.Contains(date) {
+			p, sec := s.Terms.Split(e)
+			primary += p
+			secondary += sec
+		}
+	}
+	return primary, secondary
+}
+
+// Split divides a day's worked hours between the primary and secondary codes.
+// Without secondary codes, or with more secondary hours than worked, the extra goes
+// to primary so no hour is lost or counted twice
+func (t PayTerms) Split(e DailyEntry) (primary, secondary float64) {
+	if t.SecondaryAccounting == nil || e.SecondaryHours <= 0 {
+		return e.HoursWorked, 0
+	}
+	secondary = e.SecondaryHours
+	if secondary > e.HoursWorked {
+		secondary = e.HoursWorked
+	}
+	return e.HoursWorked - secondary, secondary
+}
+
+// SecondaryRate is the secondary line's own rate, or the primary rate when it has none
+func (t PayTerms) SecondaryRate() float64 {
+	if t.SecondaryAccounting != nil && t.SecondaryAccounting.HourlyRate > 0 {
+		return t.SecondaryAccounting.HourlyRate
+	}
+	return t.Rate
+}
+
+// Helper returning the dated changes in effective order
+func (p *Profile) sortedPayChanges() []PayTerms {
+	changes := append([]PayTerms(nil), p.PayChanges...)
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].EffectiveDate < changes[j].EffectiveDate })
+	return changes
+}
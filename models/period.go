@@ -0,0 +1,190 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// PeriodKind names how a profile's pay periods are laid out
+type PeriodKind string
+
+const (
+	PeriodMonthly     PeriodKind = ""             // Calendar months, the original sheets
+	PeriodFixed       PeriodKind = "fixed"        // Every LengthDays days from Anchor (14 = biweekly)
+	PeriodSemiMonthly PeriodKind = "semi_monthly" // 1st to SplitDay, then the rest of the month
+)
+
+// PayPeriod defines the span of each timesheet for a profile. The zero value is monthly
+type PayPeriod struct {
+	Kind       PeriodKind `json:"kind,omitempty"`
+	Anchor     string     `json:"anchor,omitempty"`      // 2006-01-02, first day of any fixed period
+	LengthDays int        `json:"length_days,omitempty"` // Days per fixed period
+	SplitDay   int        `json:"split_day,omitempty"`   // Last day of the first semi-monthly half, 15 when unset
+}
+
+// Period is the inclusive run of days one timesheet covers
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Validate checks the definition before it is saved
+func (pp PayPeriod) Validate() error {
+	switch pp.Kind {
+	case PeriodMonthly:
+		return nil
+	case PeriodFixed:
+		if _, err := time.ParseInLocation("2006-01-02", pp.Anchor, time.Local); err != nil {
+			return fmt.Errorf("pay period start %q is not YYYY-MM-DD", pp.Anchor)
+		}
+		if pp.LengthDays < 7 || pp.LengthDays > 31 {
+			return errors.New("pay period length must be 7 to 31 days")
+		}
+		return nil
+	case PeriodSemiMonthly:
+		if pp.SplitDay != 0 && (pp.SplitDay < 1 || pp.SplitDay > 27) {
+			return errors.New("semi-monthly split day must be 1 to 27")
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown pay period %q", pp.Kind)
+}
+
+// PeriodFor returns the period containing date. A definition that does not validate
+// falls back to the calendar month
+func (pp PayPeriod) PeriodFor(date time.Time) Period {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local)
+	if pp.Validate() != nil {
+		return MonthPeriod(day.Year(), day.Month())
+	}
+
+	switch pp.Kind {
+	case PeriodFixed:
+		anchor, _ := time.ParseInLocation("2006-01-02", pp.Anchor, time.Local)
+		offset := daysBetween(anchor, day) % pp.LengthDays
+		if offset < 0 {
+			offset += pp.LengthDays
+		}
+		start := day.AddDate(0, 0, -offset)
+		return Period{Start: start, End: start.AddDate(0, 0, pp.LengthDays-1)}
+
+	case PeriodSemiMonthly:
+		split := pp.SplitDay
+		if split == 0 {
+			split = 15
+		}
+		first := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.Local)
+		if day.Day() <= split {
+			return Period{Start: first, End: first.AddDate(0, 0, split-1)}
+		}
+		return Period{Start: first.AddDate(0, 0, split), End: first.AddDate(0, 1, -1)}
+	}
+	return MonthPeriod(day.Year(), day.Month())
+}
+
+// Describe is a short label for the definition, e.g. "Every 14 days from 2026-09-07"
+func (pp PayPeriod) Describe() string {
+	switch pp.Kind {
+	case PeriodFixed:
+		return fmt.Sprintf("Every %d days from %s", pp.LengthDays, pp.Anchor)
+	case PeriodSemiMonthly:
+		split := pp.SplitDay
+		if split == 0 {
+			split = 15
+		}
+		return fmt.Sprintf("Semi-monthly, 1st-%d and %d-end", split, split+1)
+	}
+	return "Monthly"
+}
+
+// MonthPeriod is the calendar month as a period
+func MonthPeriod(year int, month time.Month) Period {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.Local)
+	return Period{Start: first, End: first.AddDate(0, 1, -1)}
+}
+
+// ParsePeriod rebuilds a period from stored 2006-01-02 dates
+func ParsePeriod(start, end string) (Period, error) {
+	s, err := time.ParseInLocation("2006-01-02", start, time.Local)
+	if err != nil {
+		return Period{}, err
+	}
+	e, err := time.ParseInLocation("2006-01-02", end, time.Local)
+	if err != nil {
+		return Period{}, err
+	}
+	if e.Before(s) {
+		return Period{}, fmt.Errorf("period ends %s before it starts %s", end, start)
+	}
+	return Period{Start: s, End: e}, nil
+}
+
+// Key is the start date (2006-01-02), which identifies a profile's sheet
+func (p Period) Key() string {
+	return p.Start.Format("2006-01-02")
+}
+
+// EndKey is the end date as 2006-01-02
+func (p Period) EndKey() string {
+	return p.End.Format("2006-01-02")
+}
+
+// IsMonth reports whether the period is exactly one calendar month
+func (p Period) IsMonth() bool {
+	m := MonthPeriod(p.Start.Year(), p.Start.Month())
+	return p.Key() == m.Key() && p.EndKey() == m.EndKey()
+}
+
+// Contains reports whether a date (2006-01-02) falls in the period
+func (p Period) Contains(date string) bool {
+	return date >= p.Key() && date <= p.EndKey()
+}
+
+// Days is the number of days in the period
+func (p Period) Days() int {
+	return daysBetween(p.Start, p.End) + 1
+}
+
+// Label reads "September 2026" for a month, otherwise "Sep 7 - Sep 20, 2026"
+func (p Period) Label() string {
+	if p.IsMonth() {
+		return p.Start.Format("January 2006")
+	}
+	if p.Start.Year() != p.End.Year() {
+		return p.Start.Format("Jan 2, 2006") + " - " + p.End.Format("Jan 2, 2006")
+	}
+	return p.Start.Format("Jan 2") + " - " + p.End.Format("Jan 2, 2006")
+}
+
+// FileLabel is the label used in export file names, e.g. "September_2026" or "2026-09-07_to_2026-09-20"
+func (p Period) FileLabel() string {
+	if p.IsMonth() {
+		return p.Start.Format("January_2006")
+	}
+	return p.Key() + "_to_" + p.EndKey()
+}
+
+// Helper counting calendar days from a to b, safe across DST changes
+func daysBetween(a, b time.Time) int {
+	ua := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	ub := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(ub.Sub(ua).Hours() / 24)
+}
+
+// Period returns the span the sheet covers. Sheets saved before pay periods existed
+// cover their calendar month
+func (t *Timesheet) Period() Period {
+	if p, err := ParsePeriod(t.PeriodStart, t.PeriodEnd); err == nil {
+		return p
+	}
+	return MonthPeriod(t.Year, time.Month(t.Month))
+}
+
+// SetPeriod points the sheet at a period. Month and Year follow the start date
+func (t *Timesheet) SetPeriod(p Period) {
+	t.PeriodStart = p.Key()
+	t.PeriodEnd = p.EndKey()
+	t.Month = int(p.Start.Month())
+	t.Year = p.Start.Year()
+}
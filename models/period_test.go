@@ -0,0 +1,80 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPeriodFor(t *testing.T) {
+	biweekly := PayPeriod{Kind: PeriodFixed, Anchor: "2026-09-07", LengthDays: 14}
+	leap := PayPeriod{Kind: PeriodFixed, Anchor: "2028-02-14", LengthDays: 14}
+	semi := PayPeriod{Kind: PeriodSemiMonthly}
+
+	tests := []struct {
+		name      string
+		pp        PayPeriod
+		date      string
+		wantStart string
+		wantEnd   string
+	}{
+		{"monthly", PayPeriod{}, "2026-09-15", "2026-09-01", "2026-09-30"},
+		{"monthly leap february", PayPeriod{}, "2028-02-10", "2028-02-01", "2028-02-29"},
+
+		{"biweekly anchor", biweekly, "2026-09-07", "2026-09-07", "2026-09-20"},
+		{"biweekly last day", biweekly, "2026-09-20", "2026-09-07", "2026-09-20"},
+		{"biweekly day before anchor", biweekly, "2026-09-06", "2026-08-24", "2026-09-06"},
+		{"biweekly across month end", biweekly, "2026-10-01", "2026-09-21", "2026-10-04"},
+		{"biweekly across year end", biweekly, "2026-01-01", "2025-12-29", "2026-01-11"},
+		{"biweekly leap day", leap, "2028-02-29", "2028-02-28", "2028-03-12"},
+		{"biweekly before leap day", leap, "2028-02-27", "2028-02-14", "2028-02-27"},
+
+		{"semi-monthly first half end", semi, "2026-02-15", "2026-02-01", "2026-02-15"},
+		{"semi-monthly second half start", semi, "2026-02-16", "2026-02-16", "2026-02-28"},
+		{"semi-monthly leap day", semi, "2028-02-29", "2028-02-16", "2028-02-29"},
+		{"semi-monthly 31st", semi, "2026-01-31", "2026-01-16", "2026-01-31"},
+		{"semi-monthly split day", PayPeriod{Kind: PeriodSemiMonthly, SplitDay: 10}, "2026-04-10", "2026-04-01", "2026-04-10"},
+		{"semi-monthly after split day", PayPeriod{Kind: PeriodSemiMonthly, SplitDay: 10}, "2026-04-11", "2026-04-11", "2026-04-30"},
+
+		{"invalid falls back to month", PayPeriod{Kind: PeriodFixed, Anchor: "2026-09-07", LengthDays: 3}, "2026-09-15", "2026-09-01", "2026-09-30"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			date, err := time.ParseInLocation("2006-01-02", tt.date, time.Local)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := tt.pp.PeriodFor(date)
+			if p.Key() != tt.wantStart || p.EndKey() != tt.wantEnd {
+				t.Errorf("PeriodFor(%s) = %s to %s, want %s to %s", tt.date, p.Key(), p.EndKey(), tt.wantStart, tt.wantEnd)
+			}
+			if !p.Contains(tt.date) {
+				t.Errorf("period %s to %s does not contain %s", p.Key(), p.EndKey(), tt.date)
+			}
+		})
+	}
+}
+
+func TestPeriodIsMonth(t *testing.T) {
+	tests := []struct {
+		start, end string
+		want       bool
+	}{
+		{"2026-09-01", "2026-09-30", true},
+		{"2028-02-01", "2028-02-29", true},
+		{"2026-02-01", "2026-02-15", false},
+		{"2026-09-21", "2026-10-04", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.start+"_"+tt.end, func(t *testing.T) {
+			p, err := ParsePeriod(tt.start, tt.end)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := p.IsMonth(); got != tt.want {
+				t.Errorf("IsMonth() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package models
 
 import (
+	"encoding/json"
 	"fmt"
+	"time"
 )
 
 // Timerange model for schedule
@@ -49,6 +51,12 @@ type Profile struct {
 	// Secondary accounting codes (for part-time employees with 2 rows)
 	SecondaryAccounting *AccountingCodes `json:"secondary_accounting,omitempty"`
 
+	// Span of each timesheet. The zero value is calendar months
+	PayPeriod PayPeriod `json:"pay_period,omitempty"`
+
+	// Raises and funding changes. Rate and accounting codes above apply until the first one
+	PayChanges []PayTerms `json:"pay_changes,omitempty"`
+
 	// Supervisor information
 	SupervisorName  string `json:"supervisor_name"`  // Supervisor print name
 	SupervisorPhone string `json:"supervisor_phone"` // Supervisor phone
@@ -58,14 +66,33 @@ type Profile struct {
 	OfficePhone   string `json:"office_phone"`   // Department/Office phone (different from employee phone)
 
 	// Work-Study specific
-	SemesterAllocation float64 `json:"semester_allocation,omitempty"` // Total hours allocated for the semester
-	PreviousBalance    float64 `json:"previous_balance,omitempty"`    // Balance from previous timesheet
+	SemesterStart      string   `json:"semester_start,omitempty"`      // 2006-01-02, sheets before it don't carry their balance in
+	SemesterAllocation float64  `json:"semester_allocation,omitempty"` // Total hours allocated for the semester
+	PreviousBalance    *float64 `json:"previous_balance,omitempty"`    // Hours left when the semester's first sheet starts, nil for the full allocation
 
 	//Schedule map
 	Schedule map[int]DaySchedule `json:"schedule"`
 }
 
-// Total hours calculation
+// StartingBalance is the work-study balance the first sheet of the semester starts from
+func (p *Profile) StartingBalance() float64 {
+	if p.PreviousBalance != nil {
+		return *p.PreviousBalance
+	}
+	return p.SemesterAllocation
+}
+
+// InSemester reports whether a date is on or after the semester start. Without a start
+// every date is in it
+func (p *Profile) InSemester(date time.Time) bool {
+	start, err := time.ParseInLocation("2006-01-02", p.SemesterStart, time.Local)
+	if err != nil {
+		return true
+	}
+	return !date.Before(start)
+}
+
+// TotalHours adds up the day's ranges. A range that cannot be read counts as 0
 func (ds DaySchedule) TotalHours() float64 {
 	if !ds.Active {
 		return 0.0
@@ -73,14 +100,63 @@ func (ds DaySchedule) TotalHours() float64 {
 
 	var total float64
 	for _, r := range ds.Ranges {
-		var startHour, startMin, endHour, endMin int
-		fmt.Sscanf(r.Start, "%02d:%02d", &startHour, &startMin)
-		fmt.Sscanf(r.End, "%02d:%02d", &endHour, &endMin)
+		hours, _ := r.Hours()
+		total += hours
+	}
+	return total
+}
 
-		startTotal := float64(startHour) + float64(startMin)/60.0
-		endTotal := float64(endHour) + float64(endMin)/60.0
+// OvernightHours is the part of TotalHours after midnight, on shifts that start this day
+func (ds DaySchedule) OvernightHours() float64 {
+	if !ds.Active {
+		return 0.0
+	}
 
-		total += endTotal - startTotal
+	var total float64
+	for _, r := range ds.Ranges {
+		total += r.AfterMidnight()
 	}
 	return total
 }
+
+// ProfileChanges lists the labels of the report fields that differ between two profiles.
+// The weekly schedule is not compared, it does not appear on timesheets.
+func ProfileChanges(a, b *Profile) []string {
+	if a == nil || b == nil {
+		return nil
+	}
+
+	fields := func(p *Profile) [][2]string {
+		payChanges, _ := json.Marshal(p.PayChanges)
+		secondary := ""
+		if p.SecondaryAccounting != nil {
+			secondary = fmt.Sprintf("%+v", *p.SecondaryAccounting)
+		}
+		return [][2]string{
+			{"Name", p.FirstName + " " + p.MiddleName + " " + p.MiddleInitial + " " + p.LastName},
+			{"Employee ID", p.EmployeeID},
+			{"Type", string(p.Type)},
+			{"Title", p.Title},
+			{"Position", p.PositionNum},
+			{"Department", p.Department},
+			{"Rate", fmt.Sprintf("%.2f", p.Rate)},
+			{"Location", p.Location},
+			{"FOAP", fmt.Sprintf("%+v", p.PrimaryAccounting)},
+			{"Secondary FOAP", secondary},
+			{"Supervisor", p.SupervisorName + " " + p.SupervisorPhone},
+			{"Phone", p.EmployeePhone + " " + p.OfficePhone},
+			{"Work-Study", fmt.Sprintf("%s %.2f %.2f", p.SemesterStart, p.SemesterAllocation, p.StartingBalance())},
+			{"Pay Changes", string(payChanges)},
+			{"Pay Period", p.PayPeriod.Describe()},
+		}
+	}
+
+	fa, fb := fields(a), fields(b)
+	var changed []string
+	for i := range fa {
+		if fa[i][1] != fb[i][1] {
+			changed = append(changed, fa[i][0])
+		}
+	}
+	return changed
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TimesheetRevision is one saved version of a period's timesheet. Revisions are never
+// edited or deleted.
+type TimesheetRevision struct {
+	ID          int64           `json:"id"`
+	ProfileID   int64           `json:"profile_id"`
+	Month       int             `json:"month"`
+	Year        int             `json:"year"`
+	PeriodStart string          `json:"period_start"` // Identifies the sheet, see Timesheet.Period
+	SavedAt     time.Time       `json:"saved_at"`
+	Status      TimesheetStatus `json:"status"`
+	Note        string          `json:"note,omitempty"` // e.g. "Submitted", "Restored revision 4"
+
+	Entries map[string]DailyEntry `json:"entries"` // Full snapshot after the save
+	Changes []DayChange           `json:"changes"` // Days that differ from the revision before
+}
+
+// DayChange is one day whose entry differs between two versions of a sheet
+type DayChange struct {
+	Date   string     `json:"date"`
+	Before DailyEntry `json:"before"`
+	After  DailyEntry `json:"after"`
+}
+
+// DiffEntries lists the days that differ between two entry maps, in date order.
+// A missing day is treated as an empty entry.
+func DiffEntries(before, after map[string]DailyEntry) []DayChange {
+	dates := make(map[string]bool)
+	for d := range before {
+		dates[d] = true
+	}
+	for d := range after {
+		dates[d] = true
+	}
+
+	var changes []DayChange
+	for d := range dates {
+		b, a := before[d], after[d]
+		b.Date, a.Date = d, d
+		if !SameEntry(b, a) {
+			changes = append(changes, DayChange{Date: d, Before: b, After: a})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Date < changes[j].Date })
+	return changes
+}
+
+// SameEntry compares two entries by their stored form
+func SameEntry(a, b DailyEntry) bool {
+	ja, _ := json.Marshal(a)
+	jb, _ := json.Marshal(b)
+	return string(ja) == string(jb)
+}
+
+// Describe lists the changed fields, e.g. "Worked 4.00 -> 6.00, Sick 0.00 -> 2.00". It
+// covers every field SameEntry compares
+func (c DayChange) Describe() string {
+	fields := []struct {
+		name          string
+		before, after float64
+	}{
+		{"Worked", c.Before.HoursWorked, c.After.HoursWorked},
+		{"Secondary", c.Before.SecondaryHours, c.After.SecondaryHours},
+		{"Overnight", c.Before.OvernightHours, c.After.OvernightHours},
+		{"Sick", c.Before.SickLeave, c.After.SickLeave},
+		{"Vacation", c.Before.Vacation, c.After.Vacation},
+		{"Holiday", c.Before.Holiday, c.After.Holiday},
+		{"Comp", c.Before.CompTimeTaken, c.After.CompTimeTaken},
+		{"Other", c.Before.OtherPaid, c.After.OtherPaid},
+		{"Overtime", c.Before.OvertimeHours, c.After.OvertimeHours},
+	}
+
+	var parts []string
+	for _, f := range fields {
+		if f.before != f.after {
+			parts = append(parts, fmt.Sprintf("%s %.2f -> %.2f", f.name, f.before, f.after))
+		}
+	}
+	if bp, ap := c.Before.PunchString(), c.After.PunchString(); bp != ap {
+		parts = append(parts, fmt.Sprintf("Punches [%s] -> [%s]", bp, ap))
+	}
+	if c.Before.Closure != c.After.Closure {
+		parts = append(parts, fmt.Sprintf("Closure %q -> %q", c.Before.Closure, c.After.Closure))
+	}
+	return strings.Join(parts, ", ")
+}
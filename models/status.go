@@ -0,0 +1,79 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TimesheetStatus is where a sheet is in the approval workflow
+type TimesheetStatus string
+
+const (
+	StatusDraft     TimesheetStatus = "draft"
+	StatusSubmitted TimesheetStatus = "submitted"
+	StatusApproved  TimesheetStatus = "approved"
+	StatusReturned  TimesheetStatus = "returned"
+)
+
+// ErrInvalidTransition is returned when a workflow action does not apply to the sheet's status
+var ErrInvalidTransition = errors.New("invalid timesheet status change")
+
+// Label returns the status for display, e.g. "Submitted". An empty status reads as Draft
+func (s TimesheetStatus) Label() string {
+	if s == "" {
+		s = StatusDraft
+	}
+	return strings.ToUpper(string(s[:1])) + string(s[1:])
+}
+
+// Locked reports whether the sheet's hours can no longer be edited
+func (t *Timesheet) Locked() bool {
+	return t.Status == StatusSubmitted || t.Status == StatusApproved
+}
+
+// Submit sends a draft or returned sheet for review
+func (t *Timesheet) Submit(now time.Time) error {
+	if t.Status != "" && t.Status != StatusDraft && t.Status != StatusReturned {
+		return fmt.Errorf("%w: cannot submit a %s sheet", ErrInvalidTransition, t.Status)
+	}
+	t.Status = StatusSubmitted
+	t.SubmittedAt = now
+	return nil
+}
+
+// Review approves or returns a submitted sheet. Returning a sheet needs a comment
+func (t *Timesheet) Review(approve bool, comment string, now time.Time) error {
+	if t.Status != StatusSubmitted {
+		return fmt.Errorf("%w: only submitted sheets can be reviewed", ErrInvalidTransition)
+	}
+	comment = strings.TrimSpace(comment)
+	if !approve && comment == "" {
+		return errors.New("a comment is required to return a timesheet")
+	}
+
+	t.Status = StatusApproved
+	if !approve {
+		t.Status = StatusReturned
+	}
+	t.ReviewedAt = now
+	t.ReviewerComment = comment
+	return nil
+}
+
+// Reopen moves a submitted or approved sheet back to draft. The reason is required
+func (t *Timesheet) Reopen(reason string, now time.Time) error {
+	if !t.Locked() {
+		return fmt.Errorf("%w: only submitted or approved sheets can be reopened", ErrInvalidTransition)
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return errors.New("a reason is required to reopen a timesheet")
+	}
+
+	t.Status = StatusDraft
+	t.ReopenedAt = now
+	t.ReopenReason = reason
+	return nil
+}
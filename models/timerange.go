@@ -0,0 +1,179 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidTime is returned for a time or time range that cannot be read
+var ErrInvalidTime = errors.New("invalid time")
+
+// 9, 930, 9:30, 13:00, 9am, 9:30 p.m.
+var clockPattern = regexp.MustCompile(`^(\d{1,2})(?::?(\d{2}))?\s*(am|a\.m\.|a|pm|p\.m\.|p)?$`)
+
+// One side of a range, in minutes after midnight
+type clockTime struct {
+	minutes  int
+	meridiem string // "am", "pm" or empty
+	explicit bool   // Can't be read as the other half of the day (pm suffix, 13:00, 09:00)
+}
+
+func parseClock(s string) (clockTime, error) {
+	text := strings.ToLower(strings.TrimSpace(s))
+	m := clockPattern.FindStringSubmatch(text)
+	if m == nil {
+		return clockTime{}, fmt.Errorf("%w: %q, try 9, 9:30am or 13:00", ErrInvalidTime, s)
+	}
+
+	hour, _ := strconv.Atoi(m[1])
+	minute := 0
+	if m[2] != "" {
+		minute, _ = strconv.Atoi(m[2])
+	}
+	if minute > 59 {
+		return clockTime{}, fmt.Errorf("%w: %q, minutes must be 00-59", ErrInvalidTime, s)
+	}
+
+	c := clockTime{meridiem: strings.ReplaceAll(m[3], ".", "")}
+	switch c.meridiem {
+	case "a", "p":
+		c.meridiem += "m"
+	}
+
+	if c.meridiem != "" {
+		if hour < 1 || hour > 12 {
+			return clockTime{}, fmt.Errorf("%w: %q, hour must be 1-12 with am/pm", ErrInvalidTime, s)
+		}
+		hour %= 12
+		if c.meridiem == "pm" {
+			hour += 12
+		}
+		c.explicit = true
+	} else {
+		if hour > 23 {
+			return clockTime{}, fmt.Errorf("%w: %q, hour must be 0-23", ErrInvalidTime, s)
+		}
+		// 0:30, 13:00, zero-padded 09:00 and any two-digit HH:MM such as the stored
+		// "11:00" are 24-hour times. Only short forms like 9, 5:30 or 1130 may mean pm
+		twoDigitClock := len(m[1]) == 2 && strings.Contains(text, ":")
+		c.explicit = hour == 0 || hour > 12 || (len(m[1]) == 2 && m[1][0] == '0') || twoDigitClock
+	}
+
+	c.minutes = hour*60 + minute
+	return c, nil
+}
+
+// ParseTimeRange reads a range such as "9-5", "9:30am-1pm", "13:00-17:30" or "22:00-06:00"
+// and returns it as 24-hour "15:04" times. A bare end hour before the start is read as
+// pm when that makes sense ("9-5"), otherwise the range runs past midnight.
+func ParseTimeRange(s string) (TimeRange, error) {
+	start, end, err := parseRange(s)
+	if err != nil {
+		return TimeRange{}, err
+	}
+	return TimeRange{Start: formatClock(start), End: formatClock(end)}, nil
+}
+
+// ParseTimeRanges reads a comma separated list of ranges. Empty text is no ranges
+func ParseTimeRanges(s string) ([]TimeRange, error) {
+	var ranges []TimeRange
+	for _, part := range strings.Split(s, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		r, err := ParseTimeRange(part)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+// Helper returning start and end in minutes after the start day's midnight. The end
+// is past 1440 for an overnight range
+func parseRange(s string) (int, int, error) {
+	text := strings.ReplaceAll(strings.TrimSpace(s), "–", "-")
+	parts := strings.Split(text, "-")
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+		return 0, 0, fmt.Errorf("%w range: %q, try 9-5, 9:30am-1pm or 13:00-17:30", ErrInvalidTime, strings.TrimSpace(s))
+	}
+
+	start, err := parseClock(parts[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := parseClock(parts[1])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// "1-5pm" is 1pm to 5pm
+	if !start.explicit && start.meridiem == "" && end.meridiem == "pm" && start.minutes < 12*60 && start.minutes+12*60 < end.minutes {
+		start.minutes += 12 * 60
+	}
+
+	if end.minutes == start.minutes {
+		return 0, 0, fmt.Errorf("%w range: %q starts and ends at the same time", ErrInvalidTime, strings.TrimSpace(s))
+	}
+	if end.minutes < start.minutes {
+		if !end.explicit && end.minutes+12*60 > start.minutes {
+			end.minutes += 12 * 60 // "9-5" is 9am to 5pm
+		} else {
+			end.minutes += 24 * 60 // Runs past midnight
+		}
+	}
+	return start.minutes, end.minutes, nil
+}
+
+// Helper to print minutes after midnight as "15:04"
+func formatClock(minutes int) string {
+	minutes %= 24 * 60
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// Span returns when the range starts and ends, measured from midnight of the day it
+// starts. The end is past 24h for a range that runs overnight
+func (r TimeRange) Span() (time.Duration, time.Duration, error) {
+	if r.End == "" {
+		return 0, 0, fmt.Errorf("%w range: %q has no end", ErrInvalidTime, r.Start)
+	}
+	start, end, err := parseRange(r.Start + "-" + r.End)
+	if err != nil {
+		return 0, 0, err
+	}
+	return time.Duration(start) * time.Minute, time.Duration(end) * time.Minute, nil
+}
+
+// Hours is the length of the range
+func (r TimeRange) Hours() (float64, error) {
+	start, end, err := r.Span()
+	if err != nil {
+		return 0, err
+	}
+	return (end - start).Hours(), nil
+}
+
+// Overnight reports whether the range runs past midnight
+func (r TimeRange) Overnight() bool {
+	_, end, err := r.Span()
+	return err == nil && end > 24*time.Hour
+}
+
+// AfterMidnight is how many hours of the range fall on the next day
+func (r TimeRange) AfterMidnight() float64 {
+	_, end, err := r.Span()
+	if err != nil || end <= 24*time.Hour {
+		return 0
+	}
+	return (end - 24*time.Hour).Hours()
+}
+
+// String prints the range as "09:00-17:00"
+func (r TimeRange) String() string {
+	return r.Start + "-" + r.End
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseTimeRange(t *testing.T) {
+	tests := []struct {
+		in         string
+		start, end string
+		hours      float64
+	}{
+		{"9-5", "09:00", "17:00", 8},
+		{"1-5pm", "13:00", "17:00", 4},
+		{"9:30am-1pm", "09:30", "13:00", 3.5},
+		{"13:00-17:30", "13:00", "17:30", 4.5},
+		{"10:00-2:00", "10:00", "14:00", 4},
+		{"22:00-06:00", "22:00", "06:00", 8},
+		{"22:30-11:00", "22:30", "11:00", 12.5},
+		{"20:00-12:00", "20:00", "12:00", 16},
+		{"11pm-7am", "23:00", "07:00", 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			r, err := ParseTimeRange(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r.Start != tt.start || r.End != tt.end {
+				t.Fatalf("range = %s, want %s-%s", r, tt.start, tt.end)
+			}
+
+			// The stored "15:04" form must read back as the same range
+			hours, err := r.Hours()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hours != tt.hours {
+				t.Errorf("hours = %v, want %v", hours, tt.hours)
+			}
+		})
+	}
+}
+
+func TestParseTimeRangeInvalid(t *testing.T) {
+	for _, in := range []string{"", "9", "9-9", "25:00-26:00", "9:75-10", "13pm-2pm", "abc-def"} {
+		if _, err := ParseTimeRange(in); !errors.Is(err, ErrInvalidTime) {
+			t.Errorf("ParseTimeRange(%q) err = %v, want ErrInvalidTime", in, err)
+		}
+	}
+}
+
+func TestTimeRangeAcrossMidnight(t *testing.T) {
+	tests := []struct {
+		r             TimeRange
+		start, end    time.Duration
+		afterMidnight float64
+	}{
+		{TimeRange{"09:00", "17:00"}, 9 * time.Hour, 17 * time.Hour, 0},
+		{TimeRange{"22:00", "06:00"}, 22 * time.Hour, 30 * time.Hour, 6},
+		{TimeRange{"22:30", "11:00"}, 22*time.Hour + 30*time.Minute, 35 * time.Hour, 11},
+		{TimeRange{"20:00", "12:00"}, 20 * time.Hour, 36 * time.Hour, 12},
+		{TimeRange{"23:00", "00:30"}, 23 * time.Hour, 24*time.Hour + 30*time.Minute, 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.r.String(), func(t *testing.T) {
+			start, end, err := tt.r.Span()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if start != tt.start || end != tt.end {
+				t.Errorf("span = %v-%v, want %v-%v", start, end, tt.start, tt.end)
+			}
+			if got := tt.r.AfterMidnight(); got != tt.afterMidnight {
+				t.Errorf("after midnight = %v, want %v", got, tt.afterMidnight)
+			}
+			if got := tt.r.Overnight(); got != (tt.afterMidnight > 0) {
+				t.Errorf("overnight = %v", got)
+			}
+		})
+	}
+}
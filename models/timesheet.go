@@ -1,11 +1,31 @@
 package models
 
+import (
+	"math"
+	"strings"
+	"time"
+)
+
 type DailyEntry struct {
 	Date string `json:"date"`
 
 	//Base time
 	HoursWorked float64 `json:"hours_worked"`
 
+	// Part of HoursWorked charged to the secondary accounting codes (part-time).
+	// The rest goes to the primary codes
+	SecondaryHours float64 `json:"secondary_hours,omitempty"`
+
+	// Part of HoursWorked on a shift that ran past midnight into the next day. Set from
+	// the punches or the schedule; the overtime policy decides which day it counts on
+	OvernightHours float64 `json:"overnight_hours,omitempty"`
+
+	// Holiday or closure name when the college is closed that day
+	Closure string `json:"closure,omitempty"`
+
+	// Actual clock-in/clock-out times ("15:04"). End is empty while clocked in
+	Punches []TimeRange `json:"punches,omitempty"`
+
 	// Full time specific
 	SickLeave     float64 `json:"sick_leave,omitempty"`
 	Vacation      float64 `json:"vacation,omitempty"`
@@ -24,18 +44,28 @@ type WeeklyEntry struct {
 	Days          map[string]DailyEntry `json:"days"` // Key: date string
 	RegularTotal  float64               `json:"regular_total"`
 	OvertimeTotal float64               `json:"overtime_total,omitempty"`
+	CarriedIn     float64               `json:"carried_in,omitempty"` // Hours from the previous sheet in this workweek
 }
 
 // TimesheetEntry model
 type Timesheet struct {
 	ID        int64 `json:"id"`
 	ProfileID int64 `json:"profile_id"` // Link to profile
-	Month     int   `json:"month"`
+	Month     int   `json:"month"`      // Month and year the period starts in
 	Year      int   `json:"year"`
 
+	// First and last day the sheet covers (2006-01-02). Empty on sheets saved before
+	// pay periods, which cover their calendar month. Use Period()
+	PeriodStart string `json:"period_start,omitempty"`
+	PeriodEnd   string `json:"period_end,omitempty"`
+
 	// Entries stored as json blob in DB. Marshal/Unmarshal needed later.
 	Entries map[string]DailyEntry `json:"entries"` // Kept for backward compatibility
 
+	// Earlier sheets' entries that share a workweek with this period. Loaded with
+	// db.Repository.GetCarryInEntries for overtime, never stored with this sheet.
+	CarryIn map[string]DailyEntry `json:"-"`
+
 	// Weekly entries (for better organization)
 	Weeks []WeeklyEntry `json:"weeks,omitempty"`
 
@@ -47,8 +77,94 @@ type Timesheet struct {
 	// Full-time specific: Other Paid description for the week
 	OtherPaidDescription string `json:"other_paid_description,omitempty"`
 
+	// Copy of the profile taken when the sheet was last saved. PDFs and reports use it
+	// so later profile edits do not rewrite history. Nil for sheets saved before snapshots.
+	ProfileSnapshot *Profile `json:"profile_snapshot,omitempty"`
+
+	// Why this save happened, recorded in the revision history. Not stored on the sheet
+	SaveNote string `json:"-"`
+
+	// Approval workflow. Zero times mean the step has not happened
+	Status          TimesheetStatus `json:"status,omitempty"`
+	SubmittedAt     time.Time       `json:"submitted_at,omitempty"`
+	ReviewedAt      time.Time       `json:"reviewed_at,omitempty"` // Approved or returned
+	ReviewerComment string          `json:"reviewer_comment,omitempty"`
+	ReopenedAt      time.Time       `json:"reopened_at,omitempty"`
+	ReopenReason    string          `json:"reopen_reason,omitempty"`
+
 	// Work-Study specific
 	GrossEarnings  float64 `json:"gross_earnings,omitempty"`  // Monthly gross earnings
 	CurrentBalance float64 `json:"current_balance,omitempty"` // Hours used this month
 	NewBalance     float64 `json:"new_balance,omitempty"`     // Remaining balance after this month
 }
+
+// TotalPaid returns worked hours plus every paid leave type for the day
+func (d DailyEntry) TotalPaid() float64 {
+	return d.HoursWorked + d.SickLeave + d.Vacation + d.Holiday + d.CompTimeTaken + d.OtherPaid
+}
+
+// PunchedHours totals the closed punch ranges. A punch ending before it started ran past midnight
+func (d DailyEntry) PunchedHours() float64 {
+	var total float64
+	for _, p := range d.Punches {
+		if p.End == "" {
+			continue
+		}
+		hours, _ := p.Hours()
+		total += hours
+	}
+	return total
+}
+
+// PunchedOvernightHours totals the closed punch time after midnight
+func (d DailyEntry) PunchedOvernightHours() float64 {
+	var total float64
+	for _, p := range d.Punches {
+		if p.End != "" {
+			total += p.AfterMidnight()
+		}
+	}
+	return total
+}
+
+// SpilledHours is the worked time that ran into the next day, never more than the
+// hours worked in case they were edited down by hand
+func (d DailyEntry) SpilledHours() float64 {
+	return math.Min(d.OvernightHours, d.HoursWorked)
+}
+
+// OpenPunch returns the index of the punch that is still clocked in, or -1
+func (d DailyEntry) OpenPunch() int {
+	for i, p := range d.Punches {
+		if p.End == "" {
+			return i
+		}
+	}
+	return -1
+}
+
+// PunchString lists the punches as "09:02-12:15, 13:00-..." for display
+func (d DailyEntry) PunchString() string {
+	parts := make([]string, 0, len(d.Punches))
+	for _, p := range d.Punches {
+		end := p.End
+		if end == "" {
+			end = "..."
+		}
+		parts = append(parts, p.Start+"-"+end)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ReportProfile returns the profile a report of this sheet should print: the saved
+// snapshot when there is one, otherwise the live profile
+func (t *Timesheet) ReportProfile(live *Profile) *Profile {
+	if t.ProfileSnapshot == nil {
+		return live
+	}
+	snap := *t.ProfileSnapshot
+	if live != nil {
+		snap.ID = live.ID
+	}
+	return &snap
+}
@@ -1 +1,328 @@
-package pdfgen
\ No newline at end of file
+package pdfgen
+
+import (
+	"calendar_utility_node_for_timesheets/models"
+	"calendar_utility_node_for_timesheets/rules"
+	"fmt"
+	"time"
+
+	"github.com/johnfercher/maroto/v2"
+	"github.com/johnfercher/maroto/v2/pkg/components/col"
+	"github.com/johnfercher/maroto/v2/pkg/components/image"
+	"github.com/johnfercher/maroto/v2/pkg/components/line"
+	"github.com/johnfercher/maroto/v2/pkg/components/text"
+	"github.com/johnfercher/maroto/v2/pkg/config"
+	"github.com/johnfercher/maroto/v2/pkg/consts/align"
+	"github.com/johnfercher/maroto/v2/pkg/consts/fontstyle"
+	"github.com/johnfercher/maroto/v2/pkg/core"
+	"github.com/johnfercher/maroto/v2/pkg/props"
+)
+
+// fullTimeWeek holds the per-column totals for one week of a full-time sheet
+type fullTimeWeek struct {
+	Start, End     time.Time
+	Worked         float64
+	Sick           float64
+	Vacation       float64
+	Holiday        float64
+	CompTaken      float64
+	OtherPaid      float64
+	Total          float64
+	CompTimeEarned float64
+	CarriedIn      float64 // Previous sheet's hours in the same workweek
+}
+
+// GenerateFullTimeTimesheet generates a PDF for full-time employees
+func GenerateFullTimeTimesheet(p *models.Profile, ts *models.Timesheet, outputPath string) error {
+	cfg := config.NewBuilder().
+		WithDimensions(215.9, 279.4).
+		WithLeftMargin(10).
+		WithTopMargin(2).
+		WithRightMargin(10).
+		Build()
+
+	mrt := maroto.New(cfg)
+
+	// Add header
+	addFullTimeHeader(mrt, ts)
+	addStatusStamp(mrt, ts)
+
+	// Add employee info
+	addFullTimeEmployeeInfo(mrt, p, ts)
+
+	// Add weekly leave table
+	addFullTimeTable(mrt, p, ts)
+	addEarningsSummary(mrt, p, ts)
+
+	// Add signature section
+	addFullTimeSignatures(mrt, p)
+
+	// Save PDF
+	doc, err := mrt.Generate()
+	if err != nil {
+		return err
+	}
+
+	return doc.Save(outputPath)
+}
+
+func addFullTimeHeader(mrt core.Maroto, ts *models.Timesheet) {
+	// Logo centered
+	mrt.AddRow(28,
+		image.NewFromFileCol(12, "assets/epcc_logo.png", props.Rect{
+			Center:  true,
+			Percent: 88,
+		}),
+	)
+
+	mrt.AddRow(5,
+		col.New(12).Add(
+			text.New("FULL-TIME EMPLOYEE TIMESHEET FOR", props.Text{
+				Size:  12,
+				Style: fontstyle.Bold,
+				Align: align.Center,
+			}),
+		),
+	)
+
+	// Month and Year (or the pay period's dates) with underlines and labels
+	monthName, yearStr, monthCaption, yearCaption := periodHeader(ts)
+
+	mrt.AddRow(7,
+		col.New(4),
+		col.New(2).Add(text.New(monthName, props.Text{Size: 11, Align: align.Center})),
+		col.New(2).Add(text.New(yearStr, props.Text{Size: 12, Align: align.Center})),
+		col.New(4),
+	)
+
+	mrt.AddRow(1,
+		col.New(4),
+		line.NewCol(2),
+		line.NewCol(2),
+		col.New(4),
+	)
+
+	mrt.AddRow(4,
+		col.New(4),
+		col.New(2).Add(text.New(monthCaption, props.Text{Size: 9, Align: align.Center})),
+		col.New(2).Add(text.New(yearCaption, props.Text{Size: 9, Align: align.Center})),
+		col.New(4),
+	)
+}
+
+func addFullTimeEmployeeInfo(mrt core.Maroto, p *models.Profile, ts *models.Timesheet) {
+	// Name and ID labels. Full-time forms use the full middle name.
+	mrt.AddRow(6,
+		col.New(3).Add(text.New("LAST NAME", props.Text{Size: 8, Style: fontstyle.Bold})),
+		col.New(3).Add(text.New("FIRST NAME", props.Text{Size: 8, Style: fontstyle.Bold})),
+		col.New(3).Add(text.New("MIDDLE NAME", props.Text{Size: 8, Style: fontstyle.Bold})),
+		col.New(3).Add(text.New("EMPLOYEE ID", props.Text{Size: 8, Style: fontstyle.Bold})),
+	)
+
+	middleName := p.MiddleName
+	if middleName == "" {
+		middleName = p.MiddleInitial
+	}
+
+	mrt.AddRow(5,
+		col.New(3).Add(text.New(p.LastName, props.Text{Size: 9})),
+		col.New(3).Add(text.New(p.FirstName, props.Text{Size: 9})),
+		col.New(3).Add(text.New(middleName, props.Text{Size: 9})),
+		col.New(3).Add(text.New(p.EmployeeID, props.Text{Size: 9})),
+	)
+
+	// Department, title and location
+	mrt.AddRow(6,
+		col.New(4).Add(text.New(fmt.Sprintf("DEPARTMENT: %s", p.Department), props.Text{Size: 9})),
+		col.New(4).Add(text.New(fmt.Sprintf("TITLE: %s", p.Title), props.Text{Size: 9})),
+		col.New(4).Add(text.New(fmt.Sprintf("LOCATION: %s", p.Location), props.Text{Size: 9})),
+	)
+
+	mrt.AddRow(6,
+		col.New(4).Add(text.New(fmt.Sprintf("POSITION NO: %s", p.PositionNum), props.Text{Size: 9})),
+		col.New(8),
+	)
+
+	// Primary accounting row, one per pay change in the period
+	segments := p.PeriodSegments(ts.Period())
+	for _, seg := range segments {
+		addSegmentHeading(mrt, ts, segments, seg)
+		codes := seg.Terms.PrimaryAccounting
+		mrt.AddRow(6,
+			col.New(3).Add(text.New(fmt.Sprintf("FUND: %s", codes.Fund), props.Text{Size: 8})),
+			col.New(3).Add(text.New(fmt.Sprintf("ORG: %s", codes.Organization), props.Text{Size: 8})),
+			col.New(3).Add(text.New(fmt.Sprintf("ACCT: %s", codes.Account), props.Text{Size: 8})),
+			col.New(3).Add(text.New(fmt.Sprintf("PROG: %s", codes.Program), props.Text{Size: 8})),
+		)
+	}
+}
+
+// collectFullTimeWeeks groups the period's entries into Mon-Sun weeks
+func collectFullTimeWeeks(p *models.Profile, ts *models.Timesheet) []fullTimeWeek {
+	policy := rules.PolicyFor(p.Type)
+
+	var weeks []fullTimeWeek
+	for _, w := range rules.EvaluatePeriod(ts.Entries, ts.CarryIn, ts.Period(), policy) {
+		weeks = append(weeks, fullTimeWeek{
+			Start:          w.Start,
+			End:            w.Start.AddDate(0, 0, 6),
+			Worked:         w.Result.Hours[rules.Worked],
+			Sick:           w.Result.Hours[rules.Sick],
+			Vacation:       w.Result.Hours[rules.Vacation],
+			Holiday:        w.Result.Hours[rules.Holiday],
+			CompTaken:      w.Result.Hours[rules.CompTaken],
+			OtherPaid:      w.Result.Hours[rules.OtherPaid],
+			Total:          w.Result.Total,
+			CompTimeEarned: w.Result.CompTimeEarned,
+			CarriedIn:      w.Result.CarriedIn,
+		})
+	}
+
+	return weeks
+}
+
+func addFullTimeTable(mrt core.Maroto, p *models.Profile, ts *models.Timesheet) {
+	mrt.AddRow(5,
+		col.New(2).Add(text.New("WEEK", props.Text{Size: 8, Style: fontstyle.Bold, Align: align.Center})),
+		col.New(10).Add(text.New("NUMBER OF HOURS", props.Text{Size: 8, Style: fontstyle.Bold, Align: align.Center})),
+	)
+
+	// Column headers
+	mrt.AddRow(8,
+		col.New(1).Add(text.New("FROM", props.Text{Size: 7, Align: align.Center})),
+		col.New(1).Add(text.New("TO", props.Text{Size: 7, Align: align.Center})),
+		col.New(1).Add(text.New("HOURS WORKED", props.Text{Size: 6, Align: align.Center})),
+		col.New(1).Add(text.New("SICK LEAVE", props.Text{Size: 6, Align: align.Center})),
+		col.New(1).Add(text.New("VACATION", props.Text{Size: 6, Align: align.Center})),
+		col.New(1).Add(text.New("HOLIDAY", props.Text{Size: 6, Align: align.Center})),
+		col.New(1).Add(text.New("COMP TIME TAKEN", props.Text{Size: 6, Align: align.Center})),
+		col.New(1).Add(text.New("OTHER PAID", props.Text{Size: 6, Align: align.Center})),
+		col.New(2).Add(text.New("TOTAL HOURS", props.Text{Size: 6, Align: align.Center})),
+		col.New(2).Add(text.New("COMP TIME EARNED", props.Text{Size: 6, Align: align.Center})),
+	)
+
+	mrt.AddRow(1, line.NewCol(12))
+
+	var monthly fullTimeWeek
+	for _, week := range collectFullTimeWeeks(p, ts) {
+		mrt.AddRow(7,
+			col.New(1).Add(text.New(week.Start.Format("01/02/06"), props.Text{Size: 7, Align: align.Center})),
+			col.New(1).Add(text.New(week.End.Format("01/02/06"), props.Text{Size: 7, Align: align.Center})),
+			col.New(1).Add(text.New(formatHours(week.Worked), props.Text{Size: 7, Align: align.Center})),
+			col.New(1).Add(text.New(formatHours(week.Sick), props.Text{Size: 7, Align: align.Center})),
+			col.New(1).Add(text.New(formatHours(week.Vacation), props.Text{Size: 7, Align: align.Center})),
+			col.New(1).Add(text.New(formatHours(week.Holiday), props.Text{Size: 7, Align: align.Center})),
+			col.New(1).Add(text.New(formatHours(week.CompTaken), props.Text{Size: 7, Align: align.Center})),
+			col.New(1).Add(text.New(formatHours(week.OtherPaid), props.Text{Size: 7, Align: align.Center})),
+			col.New(2).Add(text.New(formatHours(week.Total), props.Text{Size: 8, Style: fontstyle.Bold, Align: align.Center})),
+			col.New(2).Add(text.New(formatHours(week.CompTimeEarned), props.Text{Size: 7, Align: align.Center})),
+		)
+
+		// Hours from the previous sheet that count toward this week's overtime
+		if week.CarriedIn > 0 {
+			addCarryInNote(mrt, ts, week.Start, week.CarriedIn)
+		}
+
+		monthly.Worked += week.Worked
+		monthly.Sick += week.Sick
+		monthly.Vacation += week.Vacation
+		monthly.Holiday += week.Holiday
+		monthly.CompTaken += week.CompTaken
+		monthly.OtherPaid += week.OtherPaid
+		monthly.Total += week.Total
+		monthly.CompTimeEarned += week.CompTimeEarned
+	}
+
+	mrt.AddRow(2, line.NewCol(12))
+
+	// Monthly totals per column
+	mrt.AddRow(7,
+		col.New(2).Add(text.New("TOTALS", props.Text{Size: 8, Style: fontstyle.Bold, Align: align.Center})),
+		col.New(1).Add(text.New(formatHours(monthly.Worked), props.Text{Size: 7, Style: fontstyle.Bold, Align: align.Center})),
+		col.New(1).Add(text.New(formatHours(monthly.Sick), props.Text{Size: 7, Style: fontstyle.Bold, Align: align.Center})),
+		col.New(1).Add(text.New(formatHours(monthly.Vacation), props.Text{Size: 7, Style: fontstyle.Bold, Align: align.Center})),
+		col.New(1).Add(text.New(formatHours(monthly.Holiday), props.Text{Size: 7, Style: fontstyle.Bold, Align: align.Center})),
+		col.New(1).Add(text.New(formatHours(monthly.CompTaken), props.Text{Size: 7, Style: fontstyle.Bold, Align: align.Center})),
+		col.New(1).Add(text.New(formatHours(monthly.OtherPaid), props.Text{Size: 7, Style: fontstyle.Bold, Align: align.Center})),
+		col.New(2).Add(text.New(fmt.Sprintf("%.2f", monthly.Total), props.Text{Size: 10, Style: fontstyle.Bold, Align: align.Center})),
+		col.New(2).Add(text.New(formatHours(monthly.CompTimeEarned), props.Text{Size: 7, Style: fontstyle.Bold, Align: align.Center})),
+	)
+
+	mrt.AddRow(3)
+
+	// Comp time earned summary. Prefer the saved value when the sheet carries one.
+	compEarned := ts.CompTimeEarned
+	if compEarned == 0 {
+		compEarned = monthly.CompTimeEarned
+	}
+
+	mrt.AddRow(5,
+		col.New(6).Add(text.New(fmt.Sprintf("COMP TIME EARNED THIS %s: %.2f", periodNoun(ts), compEarned), props.Text{Size: 8, Style: fontstyle.Bold})),
+		col.New(6).Add(text.New(fmt.Sprintf("Comp time is earned at %.2g hours per overtime hour", rules.PolicyFor(p.Type).CompTimeRate), props.Text{Size: 7, Align: align.Right})),
+	)
+
+	// Other paid explanation
+	mrt.AddRow(5,
+		col.New(12).Add(text.New(fmt.Sprintf("OTHER PAID (EXPLAIN): %s", ts.OtherPaidDescription), props.Text{Size: 8})),
+	)
+	mrt.AddRow(1, line.NewCol(12))
+}
+
+func addFullTimeSignatures(mrt core.Maroto, p *models.Profile) {
+	mrt.AddRow(3)
+
+	mrt.AddRow(4,
+		col.New(12).Add(
+			text.New("I certify that the above time record is true and accurate.", props.Text{Size: 7}),
+		),
+	)
+
+	mrt.AddRow(2)
+
+	// Employee and Supervisor Signature labels
+	mrt.AddRow(5,
+		col.New(5).Add(text.New("Employee's Signature:", props.Text{Size: 8})),
+		col.New(2).Add(text.New("Date:", props.Text{Size: 8})),
+		col.New(3).Add(text.New("Supervisor Signature:", props.Text{Size: 8})),
+		col.New(2).Add(text.New("Date:", props.Text{Size: 8})),
+	)
+
+	mrt.AddRow(1,
+		line.NewCol(5),
+		col.New(2),
+		line.NewCol(3),
+		col.New(2),
+	)
+
+	mrt.AddRow(1,
+		col.New(5),
+		line.NewCol(2),
+		col.New(3),
+		line.NewCol(2),
+	)
+
+	mrt.AddRow(2)
+
+	// Supervisor name and phone numbers are printed from the profile
+	mrt.AddRow(5,
+		col.New(6).Add(text.New(fmt.Sprintf("Supervisor Print Name: %s", p.SupervisorName), props.Text{Size: 8})),
+		col.New(6).Add(text.New(fmt.Sprintf("Supervisor Phone: %s", p.SupervisorPhone), props.Text{Size: 8})),
+	)
+
+	mrt.AddRow(1,
+		line.NewCol(6),
+		line.NewCol(6),
+	)
+
+	mrt.AddRow(2)
+
+	mrt.AddRow(5,
+		col.New(6).Add(text.New(fmt.Sprintf("Employee Office Phone: %s", p.EmployeePhone), props.Text{Size: 8})),
+		col.New(6).Add(text.New(fmt.Sprintf("Department Phone: %s", p.OfficePhone), props.Text{Size: 8})),
+	)
+
+	mrt.AddRow(1,
+		line.NewCol(6),
+		line.NewCol(6),
+	)
+}
@@ -2,22 +2,133 @@ package pdfgen
 
 import (
 	"calendar_utility_node_for_timesheets/models"
+	"calendar_utility_node_for_timesheets/rules"
 	"fmt"
+	"strings"
+	"time"
+
+	"github.com/johnfercher/maroto/v2/pkg/components/col"
+	"github.com/johnfercher/maroto/v2/pkg/components/text"
+	"github.com/johnfercher/maroto/v2/pkg/consts/align"
+	"github.com/johnfercher/maroto/v2/pkg/consts/fontstyle"
+	"github.com/johnfercher/maroto/v2/pkg/core"
+	"github.com/johnfercher/maroto/v2/pkg/props"
 )
 
-// GenerateTimesheet is the main entry point called by UI
+// GenerateTimesheet is the main entry point called by UI. The sheet's profile snapshot
+// is printed when it has one, so old months keep the details they were submitted with.
 func GenerateTimesheet(p *models.Profile, ts *models.Timesheet, outputPath string) error {
+	p = ts.ReportProfile(p)
+
 	// Route to appropriate generator based on employee type
 	switch p.Type {
 	case models.TypePartTime:
 		return GeneratePartTimeTimesheet(p, ts, outputPath)
 	case models.TypeFullTime:
-		// TODO: Implement full-time timesheet generation
-		return fmt.Errorf("full-time timesheet generation not yet implemented")
+		return GenerateFullTimeTimesheet(p, ts, outputPath)
 	case models.TypeWorkStudy:
-		// TODO: Implement work-study timesheet generation
-		return fmt.Errorf("work-study timesheet generation not yet implemented")
+		return GenerateWorkStudyTimesheet(p, ts, outputPath)
 	default:
 		return fmt.Errorf("unknown employee type: %v", p.Type)
 	}
 }
+
+// Helper to format hours (empty string if zero)
+func formatHours(hours float64) string {
+	if hours == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.2f", hours)
+}
+
+// Note under a week row whose workweek began on the previous timesheet
+func addCarryInNote(mrt core.Maroto, ts *models.Timesheet, weekStart time.Time, carriedIn float64) {
+	lastCarried := ts.Period().Start.AddDate(0, 0, -1)
+
+	note := fmt.Sprintf("* Includes %.2f hrs worked %s-%s, reported on the previous timesheet, toward this week's overtime threshold.",
+		carriedIn, weekStart.Format("01/02"), lastCarried.Format("01/02"))
+	mrt.AddRow(4,
+		col.New(12).Add(text.New(note, props.Text{Size: 6, Style: fontstyle.Italic})),
+	)
+}
+
+// Header cells under the title: month and year for a monthly sheet, otherwise the
+// first and last day of the pay period. Returns the values and their captions
+func periodHeader(ts *models.Timesheet) (first, second, firstCaption, secondCaption string) {
+	period := ts.Period()
+	if period.IsMonth() {
+		return period.Start.Month().String(), fmt.Sprintf("%d", period.Start.Year()), "Month", "Year"
+	}
+	return period.Start.Format("01/02/2006"), period.End.Format("01/02/2006"), "Period Start", "Period End"
+}
+
+// Helper to name the span a sheet covers in labels: MONTH for a calendar month, otherwise PERIOD
+func periodNoun(ts *models.Timesheet) string {
+	if ts.Period().IsMonth() {
+		return "MONTH"
+	}
+	return "PERIOD"
+}
+
+// Stamp the approval status under the header, with the date of the last step
+func addStatusStamp(mrt core.Maroto, ts *models.Timesheet) {
+	stamp := "STATUS: " + strings.ToUpper(ts.Status.Label())
+	switch ts.Status {
+	case models.StatusSubmitted:
+		stamp += " " + ts.SubmittedAt.Format("01/02/2006 15:04")
+	case models.StatusApproved, models.StatusReturned:
+		stamp += " " + ts.ReviewedAt.Format("01/02/2006 15:04")
+	}
+
+	mrt.AddRow(5,
+		col.New(12).Add(text.New(stamp, props.Text{Size: 8, Style: fontstyle.Bold, Align: align.Right})),
+	)
+
+	if ts.ReviewerComment != "" && ts.Status != models.StatusDraft {
+		mrt.AddRow(4,
+			col.New(12).Add(text.New("Reviewer: "+ts.ReviewerComment, props.Text{Size: 6, Style: fontstyle.Italic, Align: align.Right})),
+		)
+	}
+}
+
+// Heading over a set of accounting codes when a pay change splits the period,
+// e.g. "EFFECTIVE 10/01-10/14 (32.00 HRS)". Nothing is printed for a single segment.
+func addSegmentHeading(mrt core.Maroto, ts *models.Timesheet, segments []models.TermsSegment, seg models.TermsSegment) {
+	if len(segments) < 2 {
+		return
+	}
+	heading := fmt.Sprintf("EFFECTIVE %s-%s (%.2f HRS)", seg.From.Format("01/02"), seg.To.Format("01/02"), seg.HoursWorked(ts.Entries))
+	mrt.AddRow(4,
+		col.New(12).Add(text.New(heading, props.Text{Size: 7, Style: fontstyle.Bold})),
+	)
+}
+
+// Rates across the period, e.g. "$12.00" or "$12.00/$12.50" when a raise lands mid-month
+func rateText(segments []models.TermsSegment) string {
+	rates := make([]string, 0, len(segments))
+	for _, seg := range segments {
+		rates = append(rates, fmt.Sprintf("$%.2f", seg.Terms.Rate))
+	}
+	return strings.Join(rates, "/")
+}
+
+// Estimated pay for the period from the overtime rules. Skipped when nothing is paid
+// hourly, e.g. a full-time profile without a rate
+func addEarningsSummary(mrt core.Maroto, p *models.Profile, ts *models.Timesheet) {
+	e := rules.EstimateEarnings(p, ts, rules.PolicyFor(p.Type))
+	if e.RegularPay == 0 && e.OvertimePay == 0 {
+		return
+	}
+
+	overtime := fmt.Sprintf("OVERTIME: %.2f HRS $%.2f", e.OvertimeHours, e.OvertimePay)
+	if e.CompBanked {
+		overtime = fmt.Sprintf("OVERTIME: %.2f HRS BANKED AS COMP", e.OvertimeHours)
+	}
+
+	mrt.AddRow(5,
+		col.New(3).Add(text.New("ESTIMATED EARNINGS", props.Text{Size: 8, Style: fontstyle.Bold})),
+		col.New(3).Add(text.New(fmt.Sprintf("REGULAR: %.2f HRS $%.2f", e.RegularHours, e.RegularPay), props.Text{Size: 8})),
+		col.New(4).Add(text.New(overtime, props.Text{Size: 8})),
+		col.New(2).Add(text.New(fmt.Sprintf("GROSS: $%.2f", e.Gross), props.Text{Size: 8, Style: fontstyle.Bold, Align: align.Right})),
+	)
+}
@@ -2,8 +2,8 @@ package pdfgen
 
 import (
 	"calendar_utility_node_for_timesheets/models"
+	"calendar_utility_node_for_timesheets/rules"
 	"fmt"
-	"time"
 
 	"github.com/johnfercher/maroto/v2"
 	"github.com/johnfercher/maroto/v2/pkg/components/col"
@@ -30,15 +30,17 @@ func GeneratePartTimeTimesheet(p *models.Profile, ts *models.Timesheet, outputPa
 
 	// Add header
 	addPartTimeHeader(mrt, p, ts)
+	addStatusStamp(mrt, ts)
 
 	// Add employee info
-	addPartTimeEmployeeInfo(mrt, p)
+	addPartTimeEmployeeInfo(mrt, p, ts)
 
 	// Add timesheet table
 	addPartTimeTable(mrt, p, ts)
+	addEarningsSummary(mrt, p, ts)
 
 	// Add accounting codes section
-	addPartTimeAccounting(mrt, p)
+	addPartTimeAccounting(mrt, p, ts)
 
 	// Add signature section
 	addPartTimeSignatures(mrt, p)
@@ -73,9 +75,8 @@ func addPartTimeHeader(mrt core.Maroto, p *models.Profile, ts *models.Timesheet)
 		),
 	)
 
-	// Third row: Month and Year with underlines and labels
-	monthName := time.Month(ts.Month).String()
-	yearStr := fmt.Sprintf("%d", ts.Year)
+	// Third row: Month and Year (or the pay period's dates) with underlines and labels
+	monthName, yearStr, monthCaption, yearCaption := periodHeader(ts)
 
 	// Create underlined month and year by adding a line below
 	mrt.AddRow(7,
@@ -106,13 +107,13 @@ func addPartTimeHeader(mrt core.Maroto, p *models.Profile, ts *models.Timesheet)
 	mrt.AddRow(4,
 		col.New(4),
 		col.New(2).Add(
-			text.New("Month", props.Text{
+			text.New(monthCaption, props.Text{
 				Size:  9,
 				Align: align.Center,
 			}),
 		),
 		col.New(2).Add(
-			text.New("Year", props.Text{
+			text.New(yearCaption, props.Text{
 				Size:  9,
 				Align: align.Center,
 			}),
@@ -121,7 +122,7 @@ func addPartTimeHeader(mrt core.Maroto, p *models.Profile, ts *models.Timesheet)
 	)
 }
 
-func addPartTimeEmployeeInfo(mrt core.Maroto, p *models.Profile) {
+func addPartTimeEmployeeInfo(mrt core.Maroto, p *models.Profile, ts *models.Timesheet) {
 	// First row: Name and ID fields
 	mrt.AddRow(6,
 		col.New(3).Add(
@@ -163,42 +164,49 @@ func addPartTimeEmployeeInfo(mrt core.Maroto, p *models.Profile) {
 		),
 	)
 
-	// Primary accounting row
-	mrt.AddRow(6,
-		col.New(2).Add(
-			text.New(fmt.Sprintf("FUND: %s", p.PrimaryAccounting.Fund), props.Text{Size: 8}),
-		),
-		col.New(2).Add(
-			text.New(fmt.Sprintf("ORG: %s", p.PrimaryAccounting.Organization), props.Text{Size: 8}),
-		),
-		col.New(2).Add(
-			text.New(fmt.Sprintf("ACCT: %s", p.PrimaryAccounting.Account), props.Text{Size: 8}),
-		),
-		col.New(2).Add(
-			text.New(fmt.Sprintf("PROG: %s", p.PrimaryAccounting.Program), props.Text{Size: 8}),
-		),
-		col.New(4).Add(
-			text.New(fmt.Sprintf("HOURLY RATE: $%.2f", p.Rate), props.Text{Size: 8}),
-		),
-	)
+	// Accounting rows, one set per pay change in the period
+	segments := p.PeriodSegments(ts.Period())
+	for _, seg := range segments {
+		addSegmentHeading(mrt, ts, segments, seg)
+		terms := seg.Terms
 
-	// Secondary accounting row if exists
-	if p.SecondaryAccounting != nil {
+		// Primary accounting row
 		mrt.AddRow(6,
 			col.New(2).Add(
-				text.New(fmt.Sprintf("FUND: %s", p.SecondaryAccounting.Fund), props.Text{Size: 8}),
+				text.New(fmt.Sprintf("FUND: %s", terms.PrimaryAccounting.Fund), props.Text{Size: 8}),
 			),
 			col.New(2).Add(
-				text.New(fmt.Sprintf("ORG: %s", p.SecondaryAccounting.Organization), props.Text{Size: 8}),
+				text.New(fmt.Sprintf("ORG: %s", terms.PrimaryAccounting.Organization), props.Text{Size: 8}),
 			),
 			col.New(2).Add(
-				text.New(fmt.Sprintf("ACCT: %s", p.SecondaryAccounting.Account), props.Text{Size: 8}),
+				text.New(fmt.Sprintf("ACCT: %s", terms.PrimaryAccounting.Account), props.Text{Size: 8}),
 			),
 			col.New(2).Add(
-				text.New(fmt.Sprintf("PROG: %s", p.SecondaryAccounting.Program), props.Text{Size: 8}),
+				text.New(fmt.Sprintf("PROG: %s", terms.PrimaryAccounting.Program), props.Text{Size: 8}),
+			),
+			col.New(4).Add(
+				text.New(fmt.Sprintf("HOURLY RATE: $%.2f", terms.Rate), props.Text{Size: 8}),
 			),
-			col.New(4),
 		)
+
+		// Secondary accounting row if exists
+		if terms.SecondaryAccounting != nil {
+			mrt.AddRow(6,
+				col.New(2).Add(
+					text.New(fmt.Sprintf("FUND: %s", terms.SecondaryAccounting.Fund), props.Text{Size: 8}),
+				),
+				col.New(2).Add(
+					text.New(fmt.Sprintf("ORG: %s", terms.SecondaryAccounting.Organization), props.Text{Size: 8}),
+				),
+				col.New(2).Add(
+					text.New(fmt.Sprintf("ACCT: %s", terms.SecondaryAccounting.Account), props.Text{Size: 8}),
+				),
+				col.New(2).Add(
+					text.New(fmt.Sprintf("PROG: %s", terms.SecondaryAccounting.Program), props.Text{Size: 8}),
+				),
+				col.New(4),
+			)
+		}
 	}
 }
 
@@ -231,80 +239,37 @@ func addPartTimeTable(mrt core.Maroto, p *models.Profile, ts *models.Timesheet)
 	mrt.AddRow(1, line.NewCol(12))
 
 	// Get weeks data
-	year, month := ts.Year, ts.Month
-	firstDay := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.Local)
-
-	// Find the Monday of the first week
-	weekStart := firstDay
-	for weekStart.Weekday() != time.Monday {
-		weekStart = weekStart.AddDate(0, 0, -1)
-	}
-
-	threshold := p.Type.OvertimeThreshold()
+	policy := rules.PolicyFor(p.Type)
 	var monthlyRegular, monthlyOT float64
 
-	// Generate up to 5 weeks
-	for weekNum := 0; weekNum < 5; weekNum++ {
+	for _, week := range rules.EvaluatePeriod(ts.Entries, ts.CarryIn, ts.Period(), policy) {
+		weekStart := week.Start
 		weekEnd := weekStart.AddDate(0, 0, 6) // Sunday
 
-		// Collect hours for each day of the week
-		dayHours := make([]float64, 7) // Mon-Sun
-		var weekTotal float64
-
-		for dayOffset := 0; dayOffset < 7; dayOffset++ {
-			currentDay := weekStart.AddDate(0, 0, dayOffset)
-
-			// Only count if within current month
-			if currentDay.Month() == time.Month(month) && currentDay.Year() == year {
-				dateStr := currentDay.Format("2006-01-02")
-				if entry, exists := ts.Entries[dateStr]; exists {
-					dayHours[dayOffset] = entry.HoursWorked
-					weekTotal += entry.HoursWorked
-				}
-			}
-		}
-
-		// Calculate regular and OT for this week
-		var weekRegular, weekOT float64
-		if weekTotal > threshold {
-			weekOT = weekTotal - threshold
-			weekRegular = threshold
-		} else {
-			weekRegular = weekTotal
-		}
-
-		monthlyRegular += weekRegular
-		monthlyOT += weekOT
-
-		// Helper to format hours (empty string if zero)
-		formatHours := func(hours float64) string {
-			if hours == 0 {
-				return ""
-			}
-			return fmt.Sprintf("%.2f", hours)
-		}
+		monthlyRegular += week.Result.Regular
+		monthlyOT += week.Result.Overtime
 
 		// Add week row
 		mrt.AddRow(7,
 			col.New(1).Add(text.New(weekStart.Format("01/02/06"), props.Text{Size: 7, Align: align.Center})),
 			col.New(1).Add(text.New(weekEnd.Format("01/02/06"), props.Text{Size: 7, Align: align.Center})),
-			col.New(1).Add(text.New(formatHours(dayHours[0]), props.Text{Size: 7, Align: align.Center})),
-			col.New(1).Add(text.New(formatHours(dayHours[1]), props.Text{Size: 7, Align: align.Center})),
-			col.New(1).Add(text.New(formatHours(dayHours[2]), props.Text{Size: 7, Align: align.Center})),
-			col.New(1).Add(text.New(formatHours(dayHours[3]), props.Text{Size: 7, Align: align.Center})),
-			col.New(1).Add(text.New(formatHours(dayHours[4]), props.Text{Size: 7, Align: align.Center})),
-			col.New(1).Add(text.New(formatHours(dayHours[5]), props.Text{Size: 7, Align: align.Center})),
-			col.New(1).Add(text.New(formatHours(dayHours[6]), props.Text{Size: 7, Align: align.Center})),
-			col.New(2).Add(text.New(formatHours(weekRegular), props.Text{Size: 7, Align: align.Center})),
-			col.New(1).Add(text.New(formatHours(weekTotal), props.Text{Size: 8, Style: fontstyle.Bold, Align: align.Center})),
+			col.New(1).Add(text.New(formatHours(week.Days[0].HoursWorked), props.Text{Size: 7, Align: align.Center})),
+			col.New(1).Add(text.New(formatHours(week.Days[1].HoursWorked), props.Text{Size: 7, Align: align.Center})),
+			col.New(1).Add(text.New(formatHours(week.Days[2].HoursWorked), props.Text{Size: 7, Align: align.Center})),
+			col.New(1).Add(text.New(formatHours(week.Days[3].HoursWorked), props.Text{Size: 7, Align: align.Center})),
+			col.New(1).Add(text.New(formatHours(week.Days[4].HoursWorked), props.Text{Size: 7, Align: align.Center})),
+			col.New(1).Add(text.New(formatHours(week.Days[5].HoursWorked), props.Text{Size: 7, Align: align.Center})),
+			col.New(1).Add(text.New(formatHours(week.Days[6].HoursWorked), props.Text{Size: 7, Align: align.Center})),
+			col.New(2).Add(text.New(formatHours(week.Result.Regular), props.Text{Size: 7, Align: align.Center})),
+			col.New(1).Add(text.New(formatHours(week.Result.Total), props.Text{Size: 8, Style: fontstyle.Bold, Align: align.Center})),
 		)
 
-		// Move to next week
-		weekStart = weekStart.AddDate(0, 0, 7)
+		// Part of those hours charged to the secondary FOAP
+		addSecondaryHoursRow(mrt, p, week)
 
-		// Stop if we've gone past the month
-		if weekStart.Month() != time.Month(month) && weekStart.Day() > 7 {
-			break
+		// Hours from the previous sheet that count toward this week's overtime
+		if week.Result.CarriedIn > 0 {
+			addCarryInNote(mrt, ts, week.Start, week.Result.CarriedIn)
 		}
 	}
 
@@ -331,30 +296,48 @@ func addPartTimeTable(mrt core.Maroto, p *models.Profile, ts *models.Timesheet)
 	)
 }
 
-func addPartTimeAccounting(mrt core.Maroto, p *models.Profile) {
-	mrt.AddRow(3)
+// Print the week's hours charged to the secondary FOAP under the week row, if any
+func addSecondaryHoursRow(mrt core.Maroto, p *models.Profile, week rules.Week) {
+	var days [7]float64
+	var total float64
+	for i, day := range week.Days {
+		if day.Date == "" {
+			continue
+		}
+		_, days[i] = p.TermsOn(day.Date).Split(day)
+		total += days[i]
+	}
+	if total == 0 {
+		return
+	}
 
-	mrt.AddRow(5,
-		col.New(12).Add(
-			text.New("PRIMARY ACCOUNTING CODES", props.Text{
-				Size:  9,
-				Style: fontstyle.Bold,
-			}),
-		),
+	cols := []core.Col{
+		col.New(2).Add(text.New("2ND FOAP", props.Text{Size: 6, Style: fontstyle.Italic, Align: align.Center})),
+	}
+	for _, hours := range days {
+		cols = append(cols, col.New(1).Add(text.New(formatHours(hours), props.Text{Size: 6, Style: fontstyle.Italic, Align: align.Center})))
+	}
+	cols = append(cols,
+		col.New(2),
+		col.New(1).Add(text.New(formatHours(total), props.Text{Size: 6, Style: fontstyle.Italic, Align: align.Center})),
 	)
+	mrt.AddRow(4, cols...)
+}
 
-	mrt.AddRow(5,
-		col.New(3).Add(text.New(fmt.Sprintf("Fund: %s", p.PrimaryAccounting.Fund), props.Text{Size: 8})),
-		col.New(3).Add(text.New(fmt.Sprintf("Org: %s", p.PrimaryAccounting.Organization), props.Text{Size: 8})),
-		col.New(3).Add(text.New(fmt.Sprintf("Acct: %s", p.PrimaryAccounting.Account), props.Text{Size: 8})),
-		col.New(3).Add(text.New(fmt.Sprintf("Prog: %s", p.PrimaryAccounting.Program), props.Text{Size: 8})),
-	)
+func addPartTimeAccounting(mrt core.Maroto, p *models.Profile, ts *models.Timesheet) {
+	// Pay per line comes from the same estimate as GROSS, so the rows add up to it
+	earnings := rules.EstimateEarnings(p, ts, rules.PolicyFor(p.Type))
+	segments := p.PeriodSegments(ts.Period())
+	for _, seg := range segments {
+		terms := seg.Terms
+		primaryHours, secondaryHours := seg.SplitHours(ts.Entries)
+		primaryPay, secondaryPay := earnings.SegmentPay(seg)
+		mrt.AddRow(3)
+		addSegmentHeading(mrt, ts, segments, seg)
 
-	// Secondary accounting if exists
-	if p.SecondaryAccounting != nil {
 		mrt.AddRow(5,
 			col.New(12).Add(
-				text.New("SECONDARY ACCOUNTING CODES", props.Text{
+				text.New("PRIMARY ACCOUNTING CODES", props.Text{
 					Size:  9,
 					Style: fontstyle.Bold,
 				}),
@@ -362,11 +345,34 @@ func addPartTimeAccounting(mrt core.Maroto, p *models.Profile) {
 		)
 
 		mrt.AddRow(5,
-			col.New(3).Add(text.New(fmt.Sprintf("Fund: %s", p.SecondaryAccounting.Fund), props.Text{Size: 8})),
-			col.New(3).Add(text.New(fmt.Sprintf("Org: %s", p.SecondaryAccounting.Organization), props.Text{Size: 8})),
-			col.New(3).Add(text.New(fmt.Sprintf("Acct: %s", p.SecondaryAccounting.Account), props.Text{Size: 8})),
-			col.New(3).Add(text.New(fmt.Sprintf("Prog: %s", p.SecondaryAccounting.Program), props.Text{Size: 8})),
+			col.New(2).Add(text.New(fmt.Sprintf("Fund: %s", terms.PrimaryAccounting.Fund), props.Text{Size: 8})),
+			col.New(2).Add(text.New(fmt.Sprintf("Org: %s", terms.PrimaryAccounting.Organization), props.Text{Size: 8})),
+			col.New(2).Add(text.New(fmt.Sprintf("Acct: %s", terms.PrimaryAccounting.Account), props.Text{Size: 8})),
+			col.New(2).Add(text.New(fmt.Sprintf("Prog: %s", terms.PrimaryAccounting.Program), props.Text{Size: 8})),
+			col.New(2).Add(text.New(fmt.Sprintf("Hours: %.2f", primaryHours), props.Text{Size: 8, Align: align.Right})),
+			col.New(2).Add(text.New(fmt.Sprintf("Pay: $%.2f", primaryPay), props.Text{Size: 8, Align: align.Right})),
 		)
+
+		// Secondary accounting if exists
+		if terms.SecondaryAccounting != nil {
+			mrt.AddRow(5,
+				col.New(12).Add(
+					text.New(fmt.Sprintf("SECONDARY ACCOUNTING CODES ($%.2f/HR)", terms.SecondaryRate()), props.Text{
+						Size:  9,
+						Style: fontstyle.Bold,
+					}),
+				),
+			)
+
+			mrt.AddRow(5,
+				col.New(2).Add(text.New(fmt.Sprintf("Fund: %s", terms.SecondaryAccounting.Fund), props.Text{Size: 8})),
+				col.New(2).Add(text.New(fmt.Sprintf("Org: %s", terms.SecondaryAccounting.Organization), props.Text{Size: 8})),
+				col.New(2).Add(text.New(fmt.Sprintf("Acct: %s", terms.SecondaryAccounting.Account), props.Text{Size: 8})),
+				col.New(2).Add(text.New(fmt.Sprintf("Prog: %s", terms.SecondaryAccounting.Program), props.Text{Size: 8})),
+				col.New(2).Add(text.New(fmt.Sprintf("Hours: %.2f", secondaryHours), props.Text{Size: 8, Align: align.Right})),
+				col.New(2).Add(text.New(fmt.Sprintf("Pay: $%.2f", secondaryPay), props.Text{Size: 8, Align: align.Right})),
+			)
+		}
 	}
 }
 
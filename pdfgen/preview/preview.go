@@ -0,0 +1,39 @@
+//go:build preview
+
+// Package preview draws generated PDFs as images for the desktop app. It is kept out of
+// pdfgen because MuPDF needs cgo, which the headless CLI is built without. MuPDF is
+// AGPL-3.0, so it is only linked into builds made with -tags preview (see NOTICE).
+package preview
+
+import (
+	"errors"
+	"fmt"
+	"image"
+
+	"github.com/gen2brain/go-fitz"
+)
+
+// RenderPages draws every page of a PDF as an image at dpi, for previews. Pages are
+// rasterized in-process by MuPDF, which go-fitz links into the binary, so nothing has
+// to be installed next to the app.
+func RenderPages(pdfPath string, dpi int) ([]image.Image, error) {
+	doc, err := fitz.New(pdfPath)
+	if err != nil {
+		return nil, fmt.Errorf("rendering preview: %w", err)
+	}
+	defer doc.Close()
+
+	if doc.NumPage() == 0 {
+		return nil, errors.New("rendering preview: the PDF has no pages")
+	}
+
+	pages := make([]image.Image, 0, doc.NumPage())
+	for n := 0; n < doc.NumPage(); n++ {
+		img, err := doc.ImageDPI(n, float64(dpi))
+		if err != nil {
+			return nil, fmt.Errorf("rendering preview page %d: %w", n+1, err)
+		}
+		pages = append(pages, img)
+	}
+	return pages, nil
+}
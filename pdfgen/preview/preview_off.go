@@ -0,0 +1,17 @@
+//go:build !preview
+
+package preview
+
+import (
+	"errors"
+	"image"
+)
+
+// ErrNotBuilt is returned by builds made without -tags preview, which leave MuPDF out
+var ErrNotBuilt = errors.New("PDF preview is not included in this build, export the timesheet to view it")
+
+// RenderPages always fails without MuPDF. The preview dialog shows the error and
+// Export still works
+func RenderPages(pdfPath string, dpi int) ([]image.Image, error) {
+	return nil, ErrNotBuilt
+}
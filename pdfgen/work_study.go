@@ -1 +1,297 @@
-package pdfgen
\ No newline at end of file
+package pdfgen
+
+import (
+	"calendar_utility_node_for_timesheets/models"
+	"calendar_utility_node_for_timesheets/rules"
+	"fmt"
+	"strings"
+
+	"github.com/johnfercher/maroto/v2"
+	"github.com/johnfercher/maroto/v2/pkg/components/col"
+	"github.com/johnfercher/maroto/v2/pkg/components/image"
+	"github.com/johnfercher/maroto/v2/pkg/components/line"
+	"github.com/johnfercher/maroto/v2/pkg/components/text"
+	"github.com/johnfercher/maroto/v2/pkg/config"
+	"github.com/johnfercher/maroto/v2/pkg/consts/align"
+	"github.com/johnfercher/maroto/v2/pkg/consts/fontstyle"
+	"github.com/johnfercher/maroto/v2/pkg/core"
+	"github.com/johnfercher/maroto/v2/pkg/props"
+)
+
+// GenerateWorkStudyTimesheet generates a PDF for work-study students.
+// Balance fields on ts are expected to be filled in by the caller (see db.Repository.ApplyWorkStudyBalance).
+func GenerateWorkStudyTimesheet(p *models.Profile, ts *models.Timesheet, outputPath string) error {
+	cfg := config.NewBuilder().
+		WithDimensions(215.9, 279.4).
+		WithLeftMargin(10).
+		WithTopMargin(2).
+		WithRightMargin(10).
+		Build()
+
+	mrt := maroto.New(cfg)
+
+	// Add header
+	addWorkStudyHeader(mrt, ts)
+	addStatusStamp(mrt, ts)
+
+	// Add student info
+	addWorkStudyEmployeeInfo(mrt, p, ts)
+
+	// Add daily hours table
+	addWorkStudyTable(mrt, ts)
+
+	// Add semester balance section
+	addWorkStudyBalance(mrt, p, ts)
+
+	// Add signature section
+	addWorkStudySignatures(mrt, p)
+
+	// Save PDF
+	doc, err := mrt.Generate()
+	if err != nil {
+		return err
+	}
+
+	return doc.Save(outputPath)
+}
+
+func addWorkStudyHeader(mrt core.Maroto, ts *models.Timesheet) {
+	// Logo centered
+	mrt.AddRow(28,
+		image.NewFromFileCol(12, "assets/epcc_logo.png", props.Rect{
+			Center:  true,
+			Percent: 88,
+		}),
+	)
+
+	mrt.AddRow(5,
+		col.New(12).Add(
+			text.New("WORK-STUDY STUDENT TIMESHEET FOR", props.Text{
+				Size:  12,
+				Style: fontstyle.Bold,
+				Align: align.Center,
+			}),
+		),
+	)
+
+	// Month and Year (or the pay period's dates) with underlines and labels
+	monthName, yearStr, monthCaption, yearCaption := periodHeader(ts)
+
+	mrt.AddRow(7,
+		col.New(4),
+		col.New(2).Add(text.New(monthName, props.Text{Size: 11, Align: align.Center})),
+		col.New(2).Add(text.New(yearStr, props.Text{Size: 12, Align: align.Center})),
+		col.New(4),
+	)
+
+	mrt.AddRow(1,
+		col.New(4),
+		line.NewCol(2),
+		line.NewCol(2),
+		col.New(4),
+	)
+
+	mrt.AddRow(4,
+		col.New(4),
+		col.New(2).Add(text.New(monthCaption, props.Text{Size: 9, Align: align.Center})),
+		col.New(2).Add(text.New(yearCaption, props.Text{Size: 9, Align: align.Center})),
+		col.New(4),
+	)
+}
+
+func addWorkStudyEmployeeInfo(mrt core.Maroto, p *models.Profile, ts *models.Timesheet) {
+	mrt.AddRow(6,
+		col.New(3).Add(text.New("LAST NAME", props.Text{Size: 8, Style: fontstyle.Bold})),
+		col.New(3).Add(text.New("FIRST NAME", props.Text{Size: 8, Style: fontstyle.Bold})),
+		col.New(2).Add(text.New("MI", props.Text{Size: 8, Style: fontstyle.Bold})),
+		col.New(4).Add(text.New("STUDENT ID", props.Text{Size: 8, Style: fontstyle.Bold})),
+	)
+
+	mrt.AddRow(5,
+		col.New(3).Add(text.New(p.LastName, props.Text{Size: 9})),
+		col.New(3).Add(text.New(p.FirstName, props.Text{Size: 9})),
+		col.New(2).Add(text.New(p.MiddleInitial, props.Text{Size: 9})),
+		col.New(4).Add(text.New(p.EmployeeID, props.Text{Size: 9})),
+	)
+
+	mrt.AddRow(6,
+		col.New(5).Add(text.New(fmt.Sprintf("DEPARTMENT: %s", p.Department), props.Text{Size: 9})),
+		col.New(4).Add(text.New(fmt.Sprintf("JOB TITLE: %s", p.Title), props.Text{Size: 9})),
+		col.New(3).Add(text.New("HOURLY RATE: "+rateText(p.PeriodSegments(ts.Period())), props.Text{Size: 9})),
+	)
+}
+
+func addWorkStudyTable(mrt core.Maroto, ts *models.Timesheet) {
+	mrt.AddRow(5,
+		col.New(3).Add(text.New("WEEK", props.Text{Size: 8, Style: fontstyle.Bold, Align: align.Center})),
+		col.New(9).Add(text.New("NUMBER OF HOURS", props.Text{Size: 8, Style: fontstyle.Bold, Align: align.Center})),
+	)
+
+	// Column headers
+	mrt.AddRow(5,
+		col.New(1).Add(text.New("FROM", props.Text{Size: 7, Align: align.Center})),
+		col.New(1).Add(text.New("TO", props.Text{Size: 7, Align: align.Center})),
+		col.New(1).Add(text.New("M", props.Text{Size: 7, Align: align.Center})),
+		col.New(1).Add(text.New("T", props.Text{Size: 7, Align: align.Center})),
+		col.New(1).Add(text.New("W", props.Text{Size: 7, Align: align.Center})),
+		col.New(1).Add(text.New("TH", props.Text{Size: 7, Align: align.Center})),
+		col.New(1).Add(text.New("F", props.Text{Size: 7, Align: align.Center})),
+		col.New(1).Add(text.New("S", props.Text{Size: 7, Align: align.Center})),
+		col.New(1).Add(text.New("S", props.Text{Size: 7, Align: align.Center})),
+		col.New(3).Add(text.New("TOTAL HOURS", props.Text{Size: 7, Align: align.Center})),
+	)
+
+	mrt.AddRow(1, line.NewCol(12))
+
+	period := ts.Period()
+
+	var monthlyTotal float64
+
+	for _, weekStart := range rules.PeriodWeekStarts(period) {
+		weekEnd := weekStart.AddDate(0, 0, 6)
+
+		dayHours := make([]float64, 7) // Mon-Sun
+		var weekTotal float64
+
+		for dayOffset := 0; dayOffset < 7; dayOffset++ {
+			currentDay := weekStart.AddDate(0, 0, dayOffset)
+
+			// Only count if within the pay period
+			if period.Contains(currentDay.Format("2006-01-02")) {
+				if entry, exists := ts.Entries[currentDay.Format("2006-01-02")]; exists {
+					dayHours[dayOffset] = entry.HoursWorked
+					weekTotal += entry.HoursWorked
+				}
+			}
+		}
+
+		monthlyTotal += weekTotal
+
+		mrt.AddRow(7,
+			col.New(1).Add(text.New(weekStart.Format("01/02/06"), props.Text{Size: 7, Align: align.Center})),
+			col.New(1).Add(text.New(weekEnd.Format("01/02/06"), props.Text{Size: 7, Align: align.Center})),
+			col.New(1).Add(text.New(formatHours(dayHours[0]), props.Text{Size: 7, Align: align.Center})),
+			col.New(1).Add(text.New(formatHours(dayHours[1]), props.Text{Size: 7, Align: align.Center})),
+			col.New(1).Add(text.New(formatHours(dayHours[2]), props.Text{Size: 7, Align: align.Center})),
+			col.New(1).Add(text.New(formatHours(dayHours[3]), props.Text{Size: 7, Align: align.Center})),
+			col.New(1).Add(text.New(formatHours(dayHours[4]), props.Text{Size: 7, Align: align.Center})),
+			col.New(1).Add(text.New(formatHours(dayHours[5]), props.Text{Size: 7, Align: align.Center})),
+			col.New(1).Add(text.New(formatHours(dayHours[6]), props.Text{Size: 7, Align: align.Center})),
+			col.New(3).Add(text.New(formatHours(weekTotal), props.Text{Size: 8, Style: fontstyle.Bold, Align: align.Center})),
+		)
+	}
+
+	mrt.AddRow(2, line.NewCol(12))
+
+	mrt.AddRow(4,
+		col.New(9).Add(
+			text.New("Round off hours worked to the nearest quarter hour; ¼ hr = .25; ½ hr. = .50; ¾ hr. = .75; 1 hr. = 1", props.Text{Size: 7}),
+		),
+		col.New(3).Add(
+			text.New("TOTAL HOURS", props.Text{Size: 8, Style: fontstyle.Bold, Align: align.Right}),
+		),
+	)
+
+	mrt.AddRow(6,
+		col.New(9),
+		col.New(3).Add(
+			text.New(fmt.Sprintf("%.2f", monthlyTotal), props.Text{Size: 12, Style: fontstyle.Bold, Align: align.Center}),
+		),
+	)
+}
+
+func addWorkStudyBalance(mrt core.Maroto, p *models.Profile, ts *models.Timesheet) {
+	mrt.AddRow(3)
+
+	mrt.AddRow(5,
+		col.New(12).Add(text.New("SEMESTER BALANCE", props.Text{Size: 9, Style: fontstyle.Bold})),
+	)
+
+	// Labels
+	mrt.AddRow(5,
+		col.New(2).Add(text.New("ALLOCATION", props.Text{Size: 7, Style: fontstyle.Bold, Align: align.Center})),
+		col.New(2).Add(text.New("PREVIOUS BALANCE", props.Text{Size: 7, Style: fontstyle.Bold, Align: align.Center})),
+		col.New(2).Add(text.New("HOURS THIS "+periodNoun(ts), props.Text{Size: 7, Style: fontstyle.Bold, Align: align.Center})),
+		col.New(2).Add(text.New("NEW BALANCE", props.Text{Size: 7, Style: fontstyle.Bold, Align: align.Center})),
+		col.New(2).Add(text.New("HOURLY RATE", props.Text{Size: 7, Style: fontstyle.Bold, Align: align.Center})),
+		col.New(2).Add(text.New("GROSS EARNINGS", props.Text{Size: 7, Style: fontstyle.Bold, Align: align.Center})),
+	)
+
+	// PreviousBalance is what was left before this period's hours were taken out
+	previousBalance := ts.NewBalance + ts.CurrentBalance
+	segments := p.PeriodSegments(ts.Period())
+
+	mrt.AddRow(6,
+		col.New(2).Add(text.New(fmt.Sprintf("%.2f", p.SemesterAllocation), props.Text{Size: 9, Align: align.Center})),
+		col.New(2).Add(text.New(fmt.Sprintf("%.2f", previousBalance), props.Text{Size: 9, Align: align.Center})),
+		col.New(2).Add(text.New(fmt.Sprintf("%.2f", ts.CurrentBalance), props.Text{Size: 9, Align: align.Center})),
+		col.New(2).Add(text.New(fmt.Sprintf("%.2f", ts.NewBalance), props.Text{Size: 9, Style: fontstyle.Bold, Align: align.Center})),
+		col.New(2).Add(text.New(rateText(segments), props.Text{Size: 9, Align: align.Center})),
+		col.New(2).Add(text.New(fmt.Sprintf("$%.2f", ts.GrossEarnings), props.Text{Size: 9, Style: fontstyle.Bold, Align: align.Center})),
+	)
+
+	// Gross is split at each rate change
+	if len(segments) > 1 {
+		parts := make([]string, 0, len(segments))
+		for _, seg := range segments {
+			parts = append(parts, fmt.Sprintf("$%.2f for %s-%s (%.2f hrs)",
+				seg.Terms.Rate, seg.From.Format("01/02"), seg.To.Format("01/02"), seg.HoursWorked(ts.Entries)))
+		}
+		mrt.AddRow(4,
+			col.New(12).Add(text.New("* Rate "+strings.Join(parts, ", "), props.Text{Size: 6, Style: fontstyle.Italic})),
+		)
+	}
+
+	mrt.AddRow(1, line.NewCol(12))
+
+	if ts.NewBalance < 0 {
+		mrt.AddRow(5,
+			col.New(12).Add(text.New("WARNING: Hours worked exceed the remaining semester allocation.", props.Text{Size: 8, Style: fontstyle.Bold})),
+		)
+	}
+}
+
+func addWorkStudySignatures(mrt core.Maroto, p *models.Profile) {
+	mrt.AddRow(3)
+
+	mrt.AddRow(4,
+		col.New(12).Add(
+			text.New("I certify that the above time record is true and accurate.", props.Text{Size: 7}),
+		),
+	)
+
+	mrt.AddRow(2)
+
+	mrt.AddRow(5,
+		col.New(5).Add(text.New("Supervisor Signature:", props.Text{Size: 8})),
+		col.New(2).Add(text.New("Date:", props.Text{Size: 8})),
+		col.New(3).Add(text.New("Student's Signature:", props.Text{Size: 8})),
+		col.New(2).Add(text.New("Date:", props.Text{Size: 8})),
+	)
+
+	mrt.AddRow(1,
+		line.NewCol(5),
+		col.New(2),
+		line.NewCol(3),
+		col.New(2),
+	)
+
+	mrt.AddRow(1,
+		col.New(5),
+		line.NewCol(2),
+		col.New(3),
+		line.NewCol(2),
+	)
+
+	mrt.AddRow(2)
+
+	mrt.AddRow(5,
+		col.New(6).Add(text.New(fmt.Sprintf("Supervisor Print Name: %s", p.SupervisorName), props.Text{Size: 8})),
+		col.New(6).Add(text.New(fmt.Sprintf("Supervisor Phone: %s", p.SupervisorPhone), props.Text{Size: 8})),
+	)
+
+	mrt.AddRow(1,
+		line.NewCol(6),
+		line.NewCol(6),
+	)
+}
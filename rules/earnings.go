@@ -0,0 +1,96 @@
+package rules
+
+import (
+	"math"
+
+	"calendar_utility_node_for_timesheets/models"
+)
+
+// OvertimePayRate is the multiple of the hourly rate paid for overtime hours
+const OvertimePayRate = 1.5
+
+// Earnings is the estimated pay for a pay period
+type Earnings struct {
+	RegularHours  float64
+	OvertimeHours float64
+	RegularPay    float64 // Regular hours at the rate in force each day
+	OvertimePay   float64 // Overtime hours at OvertimePayRate (or the regular rate for StraightTime), zero when the policy banks comp time
+	Gross         float64 // RegularPay plus OvertimePay
+	CompBanked    bool    // Overtime was banked as comp time instead of paid
+
+	// Each day's pay (2006-01-02) by accounting line, overtime included. Sums to Gross
+	Days map[string]LinePay
+}
+
+// LinePay is pay split between the primary and secondary accounting codes
+type LinePay struct {
+	Primary   float64
+	Secondary float64
+}
+
+// SegmentPay totals the pay of the days in a segment by accounting line
+func (e Earnings) SegmentPay(seg models.TermsSegment) (primary, secondary float64) {
+	for date, pay := range e.Days {
+		if seg.Contains(date) {
+			primary += pay.Primary
+			secondary += pay.Secondary
+		}
+	}
+	return primary, secondary
+}
+
+// EstimateEarnings prices a pay period with the overtime rules. Each day is paid at the rate
+// in force that day (see models.Profile.TermsOn), with hours charged to the secondary
+// accounting codes at the secondary rate. A week's overtime falls on its last counted
+// hours, so it is priced from Sunday back.
+func EstimateEarnings(prof *models.Profile, ts *models.Timesheet, p Policy) Earnings {
+	e := Earnings{CompBanked: p.BankCompTime, Days: make(map[string]LinePay)}
+
+	for _, w := range EvaluatePeriod(ts.Entries, ts.CarryIn, ts.Period(), p) {
+		remaining := w.Result.Overtime
+
+		for i := len(w.Days) - 1; i >= 0; i-- {
+			day := w.Days[i]
+			if day.Date == "" {
+				continue
+			}
+
+			paid := day.TotalPaid()
+			if paid == 0 {
+				continue
+			}
+			overtime := math.Min(remaining, countedHours(day, p))
+			remaining -= overtime
+
+			// Blend the day's rate across its accounting lines; leave is paid on primary
+			terms := prof.TermsOn(day.Date)
+			_, secondary := terms.Split(day)
+			rate := ((paid-secondary)*terms.Rate + secondary*terms.SecondaryRate()) / paid
+
+			var overtimePay float64
+			switch {
+			case p.BankCompTime:
+			case p.StraightTime:
+				overtimePay = overtime * rate
+			default:
+				overtimePay = overtime * rate * OvertimePayRate
+			}
+			e.RegularHours += paid - overtime
+			e.OvertimeHours += overtime
+			e.RegularPay += (paid - overtime) * rate
+			e.OvertimePay += overtimePay
+
+			// Each line gets its share of the day's pay, so the premium follows the hours
+			if full := paid * rate; full > 0 {
+				pay := (paid-overtime)*rate + overtimePay
+				e.Days[day.Date] = LinePay{
+					Primary:   pay * (paid - secondary) * terms.Rate / full,
+					Secondary: pay * secondary * terms.SecondaryRate() / full,
+				}
+			}
+		}
+	}
+
+	e.Gross = e.RegularPay + e.OvertimePay
+	return e
+}
@@ -0,0 +1,140 @@
+package rules
+
+import (
+	"testing"
+	"time"
+
+	"calendar_utility_node_for_timesheets/models"
+)
+
+// Helper building a September 2026 sheet from days of worked hours
+func septemberSheet(days ...models.DailyEntry) *models.Timesheet {
+	ts := &models.Timesheet{Entries: make(map[string]models.DailyEntry)}
+	ts.SetPeriod(models.MonthPeriod(2026, time.September))
+	for _, d := range days {
+		ts.Entries[d.Date] = d
+	}
+	return ts
+}
+
+func TestEstimateEarningsPolicies(t *testing.T) {
+	tests := []struct {
+		name         string
+		empType      models.EmployeeType
+		rate         float64
+		days         []models.DailyEntry
+		wantRegular  float64
+		wantOvertime float64
+		wantBanked   bool
+	}{
+		// Work-study overtime is paid at straight time
+		{"work-study 15", models.TypeWorkStudy, 10, workWeek("2026-09-07", 5, 5, 5, 5), 150, 50, false},
+		{"part-time 19", models.TypePartTime, 20, workWeek("2026-09-07", 4, 4, 4, 4, 4), 380, 30, false},
+		// Full-time overtime is banked as comp time, not paid
+		{"full-time 40", models.TypeFullTime, 30, workWeek("2026-09-07", 9, 9, 9, 9, 9), 1200, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prof := &models.Profile{Type: tt.empType, Rate: tt.rate}
+			e := EstimateEarnings(prof, septemberSheet(tt.days...), DefaultPolicy(tt.empType))
+			if !near(e.RegularPay, tt.wantRegular) || !near(e.OvertimePay, tt.wantOvertime) || !near(e.Gross, tt.wantRegular+tt.wantOvertime) {
+				t.Errorf("regular/overtime/gross = %v/%v/%v, want %v/%v/%v",
+					e.RegularPay, e.OvertimePay, e.Gross, tt.wantRegular, tt.wantOvertime, tt.wantRegular+tt.wantOvertime)
+			}
+			if e.CompBanked != tt.wantBanked {
+				t.Errorf("comp banked = %v, want %v", e.CompBanked, tt.wantBanked)
+			}
+		})
+	}
+}
+
+func TestEstimateEarningsDays(t *testing.T) {
+	secondary := &models.AccountingCodes{Fund: "200", HourlyRate: 30}
+
+	tests := []struct {
+		name string
+		prof models.Profile
+		days []models.DailyEntry
+		want map[string]LinePay
+	}{
+		{
+			// 22 hours, so the last 3 counted are overtime: Sunday's 2 at the raised rate, then 1 of Friday's
+			name: "overtime priced from sunday back",
+			prof: models.Profile{Type: models.TypePartTime, Rate: 20, PayChanges: []models.PayTerms{{EffectiveDate: "2026-09-13", Rate: 40}}},
+			days: workWeek("2026-09-07", 4, 4, 4, 4, 4, 0, 2),
+			want: map[string]LinePay{
+				"2026-09-07": {Primary: 80}, "2026-09-08": {Primary: 80}, "2026-09-09": {Primary: 80}, "2026-09-10": {Primary: 80},
+				"2026-09-11": {Primary: 3*20 + 1*20*1.5},
+				"2026-09-13": {Primary: 2 * 40 * 1.5},
+			},
+		},
+		{
+			// Friday has 4 primary hours at $20 and 2 secondary at $30, all 3 overtime hours land on it
+			name: "blended rate on a split day",
+			prof: models.Profile{Type: models.TypePartTime, Rate: 20, SecondaryAccounting: secondary},
+			days: func() []models.DailyEntry {
+				days := workWeek("2026-09-07", 4, 4, 4, 4, 6)
+				days[4].SecondaryHours = 2
+				return days
+			}(),
+			want: map[string]LinePay{
+				"2026-09-07": {Primary: 80}, "2026-09-08": {Primary: 80}, "2026-09-09": {Primary: 80}, "2026-09-10": {Primary: 80},
+				// Day rate 140/6, pay 3 regular + 3 at 1.5x = 175, split 80:60
+				"2026-09-11": {Primary: 100, Secondary: 75},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := EstimateEarnings(&tt.prof, septemberSheet(tt.days...), DefaultPolicy(tt.prof.Type))
+			if len(e.Days) != len(tt.want) {
+				t.Errorf("days paid = %d, want %d", len(e.Days), len(tt.want))
+			}
+
+			var total float64
+			for date, pay := range e.Days {
+				want := tt.want[date]
+				if !near(pay.Primary, want.Primary) || !near(pay.Secondary, want.Secondary) {
+					t.Errorf("%s pay = %+v, want %+v", date, pay, want)
+				}
+				total += pay.Primary + pay.Secondary
+			}
+			if !near(total, e.Gross) {
+				t.Errorf("days sum to %v, gross is %v", total, e.Gross)
+			}
+
+			var segments float64
+			for _, seg := range tt.prof.PeriodSegments(models.MonthPeriod(2026, time.September)) {
+				primary, secondary := e.SegmentPay(seg)
+				segments += primary + secondary
+			}
+			if !near(segments, e.Gross) {
+				t.Errorf("segments sum to %v, gross is %v", segments, e.Gross)
+			}
+		})
+	}
+}
+
+func TestEstimateEarningsCarryInWeek(t *testing.T) {
+	// 15 hours on the September sheet fill most of the 19, so 2 of October's 6 are overtime
+	carryIn := map[string]models.DailyEntry{}
+	for _, d := range workWeek("2026-09-28", 5, 5, 5) {
+		carryIn[d.Date] = d
+	}
+	ts := &models.Timesheet{Entries: map[string]models.DailyEntry{}, CarryIn: carryIn}
+	ts.SetPeriod(models.MonthPeriod(2026, time.October))
+	for _, d := range workWeek("2026-10-01", 3, 3) {
+		ts.Entries[d.Date] = d
+	}
+
+	prof := &models.Profile{Type: models.TypePartTime, Rate: 20}
+	e := EstimateEarnings(prof, ts, DefaultPolicy(models.TypePartTime))
+	if !near(e.RegularHours, 4) || !near(e.OvertimeHours, 2) || !near(e.Gross, 4*20+2*20*1.5) {
+		t.Errorf("regular/overtime hours = %v/%v, gross %v, want 4/2, 140", e.RegularHours, e.OvertimeHours, e.Gross)
+	}
+	if pay := e.Days["2026-10-02"]; !near(pay.Primary, 1*20+2*20*1.5) {
+		t.Errorf("Friday pay = %+v, want the overtime on it", pay)
+	}
+}
@@ -0,0 +1,286 @@
+package rules
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"calendar_utility_node_for_timesheets/models"
+)
+
+// HourType names one paid column of a DailyEntry
+type HourType string
+
+const (
+	Worked    HourType = "worked"
+	Sick      HourType = "sick"
+	Vacation  HourType = "vacation"
+	Holiday   HourType = "holiday"
+	CompTaken HourType = "comp_taken"
+	OtherPaid HourType = "other_paid"
+)
+
+// HourTypes lists every paid column in display order
+var HourTypes = []HourType{Worked, Sick, Vacation, Holiday, CompTaken, OtherPaid}
+
+// OvernightRule says which day the after-midnight part of a shift belongs to
+type OvernightRule string
+
+const (
+	OvernightStartDay OvernightRule = "start_day"      // The whole shift counts on the day it began
+	OvernightSplit    OvernightRule = "split_midnight" // Hours after midnight count on the next day
+)
+
+// Policy describes how weekly hours split into regular and overtime
+type Policy struct {
+	// Hours per Mon-Sun workweek before overtime starts
+	WeeklyThreshold float64 `json:"weekly_threshold"`
+
+	// Comp time earned per overtime hour (1.5 = time and a half)
+	CompTimeRate float64 `json:"comp_time_rate"`
+
+	// BankCompTime earns comp time for overtime instead of paying it out
+	BankCompTime bool `json:"bank_comp_time"`
+
+	// StraightTime pays overtime hours at the regular rate, without OvertimePayRate.
+	// Work-study hours are paid this way
+	StraightTime bool `json:"straight_time"`
+
+	// Hour types that count toward WeeklyThreshold. Types not listed are still paid.
+	Counts map[HourType]bool `json:"counts"`
+
+	// Which workweek a Sunday night shift counts toward. Empty is OvernightStartDay
+	Overnight OvernightRule `json:"overnight,omitempty"`
+}
+
+// WeekResult is the overtime split for one workweek
+type WeekResult struct {
+	Hours          map[HourType]float64 // Paid hours per type
+	Total          float64              // All paid hours
+	Counted        float64              // Hours that count toward the threshold
+	Regular        float64              // Total minus Overtime
+	Overtime       float64              // Counted hours over the threshold
+	CompTimeEarned float64              // Overtime banked as comp time, if the policy banks it
+	CarriedIn      float64              // Counted hours from earlier in the week on the previous sheet
+	Shifted        float64              // Counted hours moved in (+) or out (-) across midnight at the week's edges
+}
+
+// DefaultPolicy returns the built-in rules for an employee type. Every paid
+// hour counts toward the threshold, which is how the paper forms are filled.
+func DefaultPolicy(t models.EmployeeType) Policy {
+	p := Policy{
+		CompTimeRate: 1.5,
+		Counts:       make(map[HourType]bool),
+	}
+	for _, ht := range HourTypes {
+		p.Counts[ht] = true
+	}
+
+	switch t {
+	case models.TypeWorkStudy:
+		p.WeeklyThreshold = 15.0
+		p.StraightTime = true
+	case models.TypePartTime:
+		p.WeeklyThreshold = 19.0
+	default:
+		p.WeeklyThreshold = 40.0
+		p.BankCompTime = true
+	}
+	return p
+}
+
+var (
+	mu       sync.RWMutex
+	policies = make(map[models.EmployeeType]Policy)
+)
+
+// PolicyFor returns the configured policy for an employee type, or its default
+func PolicyFor(t models.EmployeeType) Policy {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if p, ok := policies[t]; ok {
+		return p
+	}
+	return DefaultPolicy(t)
+}
+
+// SetPolicy overrides the policy used for an employee type
+func SetPolicy(t models.EmployeeType, p Policy) {
+	mu.Lock()
+	defer mu.Unlock()
+	policies[t] = p
+}
+
+// HoursByType splits a day into its paid columns
+func HoursByType(d models.DailyEntry) map[HourType]float64 {
+	return map[HourType]float64{
+		Worked:    d.HoursWorked,
+		Sick:      d.SickLeave,
+		Vacation:  d.Vacation,
+		Holiday:   d.Holiday,
+		CompTaken: d.CompTimeTaken,
+		OtherPaid: d.OtherPaid,
+	}
+}
+
+// Evaluate splits one workweek of entries into regular, overtime and comp time earned
+func Evaluate(days []models.DailyEntry, p Policy) WeekResult {
+	return EvaluateWithCarry(days, 0, p)
+}
+
+// EvaluateWithCarry is Evaluate for a week that started in the previous month.
+// carriedIn counted hours were worked earlier in the same workweek, so they fill
+// the threshold first; only overtime on these days is attributed to them.
+func EvaluateWithCarry(days []models.DailyEntry, carriedIn float64, p Policy) WeekResult {
+	return evaluateWeek(days, carriedIn, 0, p)
+}
+
+// Helper doing the week's math. shifted counted hours are moved across the week's
+// edges by the overnight rule; hours paid on another week never become overtime here
+func evaluateWeek(days []models.DailyEntry, carriedIn float64, shifted float64, p Policy) WeekResult {
+	res := WeekResult{Hours: make(map[HourType]float64), CarriedIn: carriedIn, Shifted: shifted}
+
+	for _, d := range days {
+		for ht, hours := range HoursByType(d) {
+			res.Hours[ht] += hours
+			res.Total += hours
+			if p.Counts[ht] {
+				res.Counted += hours
+			}
+		}
+	}
+
+	ownCounted := res.Counted
+	res.Counted += shifted
+	if over := carriedIn + res.Counted - p.WeeklyThreshold; over > 0 {
+		res.Overtime = math.Max(0, math.Min(over, math.Min(res.Counted, ownCounted)))
+	}
+	res.Regular = res.Total - res.Overtime
+
+	if p.BankCompTime {
+		res.CompTimeEarned = res.Overtime * p.CompTimeRate
+	}
+	return res
+}
+
+// Week is one Mon-Sun row of a pay period
+type Week struct {
+	Start  time.Time
+	Days   [7]models.DailyEntry // Mon-Sun, zero value for days outside the period
+	Result WeekResult
+}
+
+// EvaluateMonth is EvaluatePeriod over a calendar month
+func EvaluateMonth(entries, carryIn map[string]models.DailyEntry, year int, month time.Month, p Policy) []Week {
+	return EvaluatePeriod(entries, carryIn, models.MonthPeriod(year, month), p)
+}
+
+// EvaluatePeriod groups a period's entries into Mon-Sun weeks and evaluates each.
+// Days outside the period are left empty. carryIn holds entries from earlier sheets
+// (see db.Repository.GetCarryInEntries) so a week that straddles the period's start
+// is measured over the whole workweek; it may be nil. Under OvernightSplit the
+// after-midnight part of a Sunday night shift counts toward the following week.
+func EvaluatePeriod(entries, carryIn map[string]models.DailyEntry, period models.Period, p Policy) []Week {
+	var weeks []Week
+	for _, start := range PeriodWeekStarts(period) {
+		w := Week{Start: start}
+		var carried float64
+		for i := 0; i < 7; i++ {
+			day := start.AddDate(0, 0, i)
+			dateStr := day.Format("2006-01-02")
+			if day.Before(period.Start) {
+				carried += countedHours(carryIn[dateStr], p)
+				continue
+			}
+			if day.After(period.End) {
+				continue
+			}
+			w.Days[i] = entries[dateStr]
+		}
+
+		var shifted float64
+		if p.Overnight == OvernightSplit {
+			// Last Sunday's night shift runs into this Monday, this Sunday's into next week
+			prevSunday := start.AddDate(0, 0, -1).Format("2006-01-02")
+			prev, ok := entries[prevSunday]
+			if !ok {
+				prev = carryIn[prevSunday]
+			}
+			shifted = spilledCounted(prev, p) - spilledCounted(w.Days[6], p)
+		}
+
+		w.Result = evaluateWeek(w.Days[:], carried, shifted, p)
+		weeks = append(weeks, w)
+	}
+	return weeks
+}
+
+// Hours of one day that count toward the threshold under p
+func countedHours(d models.DailyEntry, p Policy) float64 {
+	var total float64
+	for ht, hours := range HoursByType(d) {
+		if p.Counts[ht] {
+			total += hours
+		}
+	}
+	return total
+}
+
+// Worked hours after midnight that count toward the threshold under p
+func spilledCounted(d models.DailyEntry, p Policy) float64 {
+	if !p.Counts[Worked] {
+		return 0
+	}
+	return d.SpilledHours()
+}
+
+// WeekStarts returns the Monday of every workweek that overlaps the month
+func WeekStarts(year int, month time.Month) []time.Time {
+	return PeriodWeekStarts(models.MonthPeriod(year, month))
+}
+
+// PeriodWeekStarts returns the Monday of every workweek that overlaps the period
+func PeriodWeekStarts(period models.Period) []time.Time {
+	// Find the Monday of the first week
+	weekStart := period.Start
+	for weekStart.Weekday() != time.Monday {
+		weekStart = weekStart.AddDate(0, 0, -1)
+	}
+
+	var starts []time.Time
+	for ; !weekStart.After(period.End); weekStart = weekStart.AddDate(0, 0, 7) {
+		starts = append(starts, weekStart)
+	}
+	return starts
+}
+
+// Summarize rebuilds ts.Weeks and the period totals from its entries and ts.CarryIn
+func Summarize(ts *models.Timesheet, p Policy) {
+	ts.Weeks = nil
+	ts.TotalWorked = 0
+	ts.TotalOvertime = 0
+	ts.CompTimeEarned = 0
+
+	for _, w := range EvaluatePeriod(ts.Entries, ts.CarryIn, ts.Period(), p) {
+		week := models.WeeklyEntry{
+			WeekStartDate: w.Start.Format("2006-01-02"),
+			WeekEndDate:   w.Start.AddDate(0, 0, 6).Format("2006-01-02"),
+			Days:          make(map[string]models.DailyEntry),
+			RegularTotal:  w.Result.Regular,
+			OvertimeTotal: w.Result.Overtime,
+			CarriedIn:     w.Result.CarriedIn,
+		}
+		for i := 0; i < 7; i++ {
+			dateStr := w.Start.AddDate(0, 0, i).Format("2006-01-02")
+			if entry, ok := ts.Entries[dateStr]; ok {
+				week.Days[dateStr] = entry
+			}
+		}
+
+		ts.TotalWorked += w.Result.Hours[Worked]
+		ts.TotalOvertime += w.Result.Overtime
+		ts.CompTimeEarned += w.Result.CompTimeEarned
+		ts.Weeks = append(ts.Weeks, week)
+	}
+}
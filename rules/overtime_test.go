@@ -0,0 +1,113 @@
+package rules
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"calendar_utility_node_for_timesheets/models"
+)
+
+// Helper comparing hours and dollars without float noise
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+// Helper building a Mon-Sun week of worked hours starting on the given Monday
+func workWeek(monday string, hours ...float64) []models.DailyEntry {
+	start, _ := time.ParseInLocation("2006-01-02", monday, time.Local)
+	days := make([]models.DailyEntry, len(hours))
+	for i, h := range hours {
+		date := start.AddDate(0, 0, i).Format("2006-01-02")
+		days[i] = models.DailyEntry{Date: date, HoursWorked: h}
+	}
+	return days
+}
+
+func TestEvaluateDefaultPolicies(t *testing.T) {
+	tests := []struct {
+		name         string
+		empType      models.EmployeeType
+		days         []models.DailyEntry
+		carriedIn    float64
+		wantRegular  float64
+		wantOvertime float64
+		wantComp     float64
+	}{
+		{"work-study under 15", models.TypeWorkStudy, workWeek("2026-09-07", 5, 5, 5), 0, 15, 0, 0},
+		{"work-study over 15", models.TypeWorkStudy, workWeek("2026-09-07", 5, 5, 5, 5), 0, 15, 5, 0},
+		{"part-time under 19", models.TypePartTime, workWeek("2026-09-07", 4, 4, 4, 4, 3), 0, 19, 0, 0},
+		{"part-time over 19", models.TypePartTime, workWeek("2026-09-07", 4, 4, 4, 4, 4), 0, 19, 1, 0},
+		{"full-time at 40", models.TypeFullTime, workWeek("2026-09-07", 8, 8, 8, 8, 8), 0, 40, 0, 0},
+		{"full-time over 40 banks comp", models.TypeFullTime, workWeek("2026-09-07", 9, 9, 9, 9, 9), 0, 40, 5, 7.5},
+		{"full-time carry-in fills threshold first", models.TypeFullTime, workWeek("2026-09-07", 0, 0, 0, 8, 7), 30, 10, 5, 7.5},
+		{"full-time carry-in over threshold", models.TypeFullTime, workWeek("2026-09-07", 0, 0, 0, 0, 8), 45, 0, 8, 12},
+		{"part-time carry-in", models.TypePartTime, workWeek("2026-09-07", 0, 0, 0, 3, 3), 15, 4, 2, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := EvaluateWithCarry(tt.days, tt.carriedIn, DefaultPolicy(tt.empType))
+			if !near(res.Regular, tt.wantRegular) || !near(res.Overtime, tt.wantOvertime) || !near(res.CompTimeEarned, tt.wantComp) {
+				t.Errorf("regular/overtime/comp = %v/%v/%v, want %v/%v/%v",
+					res.Regular, res.Overtime, res.CompTimeEarned, tt.wantRegular, tt.wantOvertime, tt.wantComp)
+			}
+			if res.CarriedIn != tt.carriedIn {
+				t.Errorf("carried in = %v, want %v", res.CarriedIn, tt.carriedIn)
+			}
+		})
+	}
+}
+
+func TestEvaluateUncountedHours(t *testing.T) {
+	p := DefaultPolicy(models.TypeFullTime)
+	p.Counts[Sick] = false
+
+	days := workWeek("2026-09-07", 8, 8, 8, 8, 8)
+	days[4].SickLeave = 8
+	res := Evaluate(days, p)
+	if res.Total != 48 || res.Counted != 40 || res.Overtime != 0 {
+		t.Errorf("total/counted/overtime = %v/%v/%v, want 48/40/0", res.Total, res.Counted, res.Overtime)
+	}
+}
+
+func TestEvaluatePeriodCarryInWeek(t *testing.T) {
+	// October 2026 starts on a Thursday, so its first workweek began on the September sheet
+	period := models.MonthPeriod(2026, time.October)
+	carryIn := map[string]models.DailyEntry{}
+	for _, d := range workWeek("2026-09-28", 8, 8, 8) {
+		carryIn[d.Date] = d
+	}
+	entries := map[string]models.DailyEntry{}
+	for _, d := range workWeek("2026-09-28", 0, 0, 0, 10, 10) {
+		if d.Date >= period.Key() {
+			entries[d.Date] = d
+		}
+	}
+
+	weeks := EvaluatePeriod(entries, carryIn, period, DefaultPolicy(models.TypeFullTime))
+	if len(weeks) != 5 {
+		t.Fatalf("weeks = %d, want 5", len(weeks))
+	}
+	first := weeks[0]
+	if got := first.Start.Format("2006-01-02"); got != "2026-09-28" {
+		t.Errorf("first week starts %s, want 2026-09-28", got)
+	}
+	for i := 0; i < 3; i++ {
+		if first.Days[i].Date != "" {
+			t.Errorf("day %d of the first week is on the previous sheet, got %+v", i, first.Days[i])
+		}
+	}
+	if first.Result.CarriedIn != 24 || first.Result.Total != 20 || first.Result.Overtime != 4 || first.Result.CompTimeEarned != 6 {
+		t.Errorf("carried/total/overtime/comp = %v/%v/%v/%v, want 24/20/4/6",
+			first.Result.CarriedIn, first.Result.Total, first.Result.Overtime, first.Result.CompTimeEarned)
+	}
+
+	ts := models.Timesheet{Entries: entries, CarryIn: carryIn}
+	ts.SetPeriod(period)
+	Summarize(&ts, DefaultPolicy(models.TypeFullTime))
+	if ts.TotalWorked != 20 || ts.TotalOvertime != 4 || ts.CompTimeEarned != 6 || ts.Weeks[0].CarriedIn != 24 {
+		t.Errorf("worked/overtime/comp/carried = %v/%v/%v/%v, want 20/4/6/24",
+			ts.TotalWorked, ts.TotalOvertime, ts.CompTimeEarned, ts.Weeks[0].CarriedIn)
+	}
+}
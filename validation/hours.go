@@ -0,0 +1,149 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"calendar_utility_node_for_timesheets/models"
+)
+
+// MaxHoursPerDay caps the paid hours of one day across every column
+const MaxHoursPerDay = 24.0
+
+var (
+	ErrNotNumber = errors.New("hours must be a number")
+	ErrNegative  = errors.New("hours cannot be negative")
+	ErrTooMany   = fmt.Errorf("more than %g hours in one day", MaxHoursPerDay)
+	ErrSplit     = errors.New("secondary hours are more than hours worked")
+)
+
+// RoundMode says which way hours move to the rounding increment
+type RoundMode string
+
+const (
+	RoundNearest RoundMode = "nearest"
+	RoundUp      RoundMode = "up"
+	RoundDown    RoundMode = "down"
+	RoundOff     RoundMode = "off" // Keep hours as entered
+)
+
+// RoundModes lists the modes in display order
+var RoundModes = []RoundMode{RoundNearest, RoundUp, RoundDown, RoundOff}
+
+// Rounding is the rule applied to every hours input
+type Rounding struct {
+	Increment float64   `json:"increment"` // 0.25 = quarter hour
+	Mode      RoundMode `json:"mode"`
+}
+
+// DefaultRounding rounds to the nearest quarter hour, as the paper forms ask
+func DefaultRounding() Rounding {
+	return Rounding{Increment: 0.25, Mode: RoundNearest}
+}
+
+var (
+	mu       sync.RWMutex
+	rounding = DefaultRounding()
+)
+
+// CurrentRounding returns the active rounding rule
+func CurrentRounding() Rounding {
+	mu.RLock()
+	defer mu.RUnlock()
+	return rounding
+}
+
+// SetRounding replaces the active rounding rule
+func SetRounding(r Rounding) {
+	mu.Lock()
+	defer mu.Unlock()
+	rounding = r
+}
+
+// Round moves hours to the rule's increment
+func (r Rounding) Round(hours float64) float64 {
+	if r.Mode == RoundOff || r.Increment <= 0 {
+		return hours
+	}
+
+	// Snap away float noise first so 7.25 is not rounded up to 7.5
+	steps := math.Round(hours/r.Increment*1e6) / 1e6
+	switch r.Mode {
+	case RoundUp:
+		steps = math.Ceil(steps)
+	case RoundDown:
+		steps = math.Floor(steps)
+	default:
+		steps = math.Round(steps)
+	}
+	return steps * r.Increment
+}
+
+// ParseHours reads one hours input and rounds it with the active rule. Empty text is 0
+func ParseHours(text string) (float64, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0, nil
+	}
+
+	hours, err := strconv.ParseFloat(text, 64)
+	if err != nil || math.IsNaN(hours) || math.IsInf(hours, 0) {
+		return 0, ErrNotNumber
+	}
+	if hours < 0 {
+		return 0, ErrNegative
+	}
+	if hours > MaxHoursPerDay {
+		return 0, ErrTooMany
+	}
+	return CurrentRounding().Round(hours), nil
+}
+
+// CheckHours is ParseHours for use as an input validator
+func CheckHours(text string) error {
+	_, err := ParseHours(text)
+	return err
+}
+
+// ValidateDay checks the totals of one day
+func ValidateDay(e models.DailyEntry) error {
+	fields := []float64{e.HoursWorked, e.SecondaryHours, e.SickLeave, e.Vacation, e.Holiday, e.CompTimeTaken, e.OtherPaid}
+	for _, hours := range fields {
+		if hours < 0 {
+			return ErrNegative
+		}
+	}
+	if e.TotalPaid() > MaxHoursPerDay {
+		return ErrTooMany
+	}
+	if e.SecondaryHours > e.HoursWorked {
+		return ErrSplit
+	}
+	return nil
+}
+
+// ValidateEntries checks every day of a sheet and reports the first bad one
+func ValidateEntries(entries map[string]models.DailyEntry) error {
+	dates := make([]string, 0, len(entries))
+	for date := range entries {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	for _, date := range dates {
+		if err := ValidateDay(entries[date]); err != nil {
+			label := date
+			if d, perr := time.Parse("2006-01-02", date); perr == nil {
+				label = d.Format("Mon Jan 2")
+			}
+			return fmt.Errorf("%s: %w", label, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+
+	"calendar_utility_node_for_timesheets/models"
+)
+
+// Helper to run a test under a rounding rule and put the previous one back after
+func withRounding(t *testing.T, r Rounding) {
+	t.Helper()
+	prev := CurrentRounding()
+	SetRounding(r)
+	t.Cleanup(func() { SetRounding(prev) })
+}
+
+func TestParseHours(t *testing.T) {
+	withRounding(t, DefaultRounding())
+
+	tests := []struct {
+		text    string
+		want    float64
+		wantErr error
+	}{
+		{"", 0, nil},
+		{"   ", 0, nil},
+		{"8", 8, nil},
+		{" 7.5 ", 7.5, nil},
+		{"7.3", 7.25, nil},
+		{"0", 0, nil},
+		{"24", 24, nil},
+		{"24.01", 0, ErrTooMany},
+		{"25", 0, ErrTooMany},
+		{"-1", 0, ErrNegative},
+		{"-0.25", 0, ErrNegative},
+		{"abc", 0, ErrNotNumber},
+		{"8h", 0, ErrNotNumber},
+		{"7,5", 0, ErrNotNumber},
+		{"NaN", 0, ErrNotNumber},
+		{"Inf", 0, ErrNotNumber},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := ParseHours(tt.text)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseHours(%q) error = %v, want %v", tt.text, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseHours(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+			if checkErr := CheckHours(tt.text); !errors.Is(checkErr, tt.wantErr) {
+				t.Errorf("CheckHours(%q) = %v, want %v", tt.text, checkErr, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRound(t *testing.T) {
+	quarter := func(mode RoundMode) Rounding { return Rounding{Increment: 0.25, Mode: mode} }
+
+	tests := []struct {
+		name  string
+		r     Rounding
+		hours float64
+		want  float64
+	}{
+		{"nearest down", quarter(RoundNearest), 7.1, 7},
+		{"nearest up", quarter(RoundNearest), 7.2, 7.25},
+		{"nearest exact", quarter(RoundNearest), 7.25, 7.25},
+		{"up", quarter(RoundUp), 7.01, 7.25},
+		{"up exact", quarter(RoundUp), 7.25, 7.25},
+		{"up float noise", quarter(RoundUp), 0.1 + 0.2 + 6.95, 7.25},
+		{"down", quarter(RoundDown), 7.24, 7},
+		{"down exact", quarter(RoundDown), 7.5, 7.5},
+		{"off", quarter(RoundOff), 7.13, 7.13},
+		{"no increment", Rounding{Mode: RoundNearest}, 7.13, 7.13},
+		{"tenth of an hour", Rounding{Increment: 0.1, Mode: RoundNearest}, 7.13, 7.1},
+		{"unknown mode rounds to nearest", Rounding{Increment: 0.5, Mode: "sideways"}, 7.3, 7.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.r.Round(tt.hours)
+			if diff := got - tt.want; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("Round(%v) = %v, want %v", tt.hours, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseHoursRoundingModes(t *testing.T) {
+	tests := []struct {
+		mode RoundMode
+		want float64
+	}{
+		{RoundNearest, 7.25},
+		{RoundUp, 7.5},
+		{RoundDown, 7.25},
+		{RoundOff, 7.3},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			withRounding(t, Rounding{Increment: 0.25, Mode: tt.mode})
+			got, err := ParseHours("7.3")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseHours(7.3) = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateDay(t *testing.T) {
+	tests := []struct {
+		name    string
+		entry   models.DailyEntry
+		wantErr error
+	}{
+		{"empty", models.DailyEntry{}, nil},
+		{"full day", models.DailyEntry{HoursWorked: 8}, nil},
+		{"exactly 24 across columns", models.DailyEntry{HoursWorked: 16, SickLeave: 4, Vacation: 4}, nil},
+		{"secondary within worked", models.DailyEntry{HoursWorked: 8, SecondaryHours: 3}, nil},
+		{"negative worked", models.DailyEntry{HoursWorked: -1}, ErrNegative},
+		{"negative secondary", models.DailyEntry{HoursWorked: 8, SecondaryHours: -1}, ErrNegative},
+		{"negative leave", models.DailyEntry{Vacation: -2}, ErrNegative},
+		{"worked over 24", models.DailyEntry{HoursWorked: 25}, ErrTooMany},
+		{"columns over 24", models.DailyEntry{HoursWorked: 20, Holiday: 8}, ErrTooMany},
+		{"secondary over worked", models.DailyEntry{HoursWorked: 4, SecondaryHours: 5}, ErrSplit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateDay(tt.entry); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateDay() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateEntriesNamesFirstBadDay(t *testing.T) {
+	entries := map[string]models.DailyEntry{
+		"2026-09-08": {Date: "2026-09-08", HoursWorked: 30},
+		"2026-09-07": {Date: "2026-09-07", HoursWorked: -1},
+		"2026-09-09": {Date: "2026-09-09", HoursWorked: 8},
+	}
+
+	err := ValidateEntries(entries)
+	if !errors.Is(err, ErrNegative) {
+		t.Fatalf("ValidateEntries() = %v, want %v", err, ErrNegative)
+	}
+	if want := "Mon Sep 7: " + ErrNegative.Error(); err.Error() != want {
+		t.Errorf("ValidateEntries() = %q, want %q", err.Error(), want)
+	}
+}